@@ -0,0 +1,105 @@
+package paste
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+// Account is the account of a token, see WhoAmI.
+type Account struct {
+	Name         string    `json:"name"`
+	Scopes       []string  `json:"scopes"`        // Scopes of the token, such as "upload"
+	TokenExpires time.Time `json:"token_expires"` // IsZero if the token doesn't expire
+}
+
+func whoAmI(req *request, options ...Option) (Account, error) {
+	req.apply(options)
+
+	if req.tok == "" {
+		return Account{}, errors.New("WhoAmI requires a token")
+	}
+	var account Account
+	err := req.doJSON("GET", req.endpoint("account"), nil, &account)
+	if err != nil {
+		return Account{}, err
+	}
+	return account, nil
+}
+
+// WhoAmI checks the Token and returns its account, to fail early
+// on bad credentials. An invalid or expired token is ErrUnauthorized.
+func WhoAmI(options ...Option) (Account, error) {
+	return whoAmI(&request{}, options...)
+}
+
+// doJSON sends a request with in encoded as JSON, unless nil,
+// and decodes the JSON response into out, unless nil.
+func (req *request) doJSON(method, rawurl string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	hr, err := req.newRequest(method, rawurl, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		hr.Header.Set("Content-Type", "application/json")
+	}
+	hr.Header.Set("Accept", "application/json")
+
+	resp, err := req.do(hr)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		result, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return transportError(hr, err)
+		}
+		return apiError(resp, result)
+	}
+	if out == nil || resp.StatusCode == 204 {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Quota is the usage and limits of an account, see GetQuota.
+type Quota struct {
+	StorageUsed      int64     `json:"storage_used"`      // Bytes
+	StorageLimit     int64     `json:"storage_limit"`     // Bytes, -1 if unlimited
+	PasteCount       int64     `json:"paste_count"`       // Pastes of the account
+	MaxPasteSize     int64     `json:"max_paste_size"`    // Bytes
+	UploadsRemaining int64     `json:"uploads_remaining"` // Uploads left today, -1 if unlimited
+	UploadsReset     time.Time `json:"uploads_reset"`     // When UploadsRemaining resets
+}
+
+func getQuota(req *request, options ...Option) (Quota, error) {
+	req.apply(options)
+
+	if req.tok == "" {
+		return Quota{}, errors.New("GetQuota requires a token")
+	}
+	var quota Quota
+	err := req.doJSON("GET", req.endpoint("account/quota"), nil, &quota)
+	if err != nil {
+		return Quota{}, err
+	}
+	return quota, nil
+}
+
+// GetQuota gets the storage and upload usage and limits of the Token account,
+// such as to check a large import fits before starting it.
+func GetQuota(options ...Option) (Quota, error) {
+	return getQuota(&request{}, options...)
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"paste.run"
+)
+
+// DeviceLogin logs in with the OAuth device authorization flow:
+// it prints the verification URL and code for the user to approve,
+// waits for the approval and stores the new token for the "default" account.
+// The token is also returned if storing it fails.
+// The options are for the requests, such as paste.BaseURL.
+func DeviceLogin(ctx context.Context, options ...paste.Option) (string, error) {
+	d, err := paste.StartDeviceLogin(append([]paste.Option{paste.Context(ctx)}, options...)...)
+	if err != nil {
+		return "", err
+	}
+	if d.VerificationURLComplete != "" {
+		fmt.Fprintf(os.Stderr, "To log in, open %s\nand confirm the code %s\n", d.VerificationURLComplete, d.UserCode)
+	} else {
+		fmt.Fprintf(os.Stderr, "To log in, open %s\nand enter the code %s\n", d.VerificationURL, d.UserCode)
+	}
+	ctx, cancel := context.WithDeadline(ctx, d.Expires)
+	defer cancel()
+	token, err := d.Wait(ctx)
+	if errors.Is(err, context.DeadlineExceeded) && !time.Now().Before(d.Expires) {
+		err = paste.ErrLoginExpired
+	}
+	if err != nil {
+		return "", err
+	}
+	return token, SetToken("default", token)
+}
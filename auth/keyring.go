@@ -0,0 +1,151 @@
+// Package auth stores the paste.run API token in the OS credential store:
+// the Keychain on macOS, the Secret Service on Linux with secret-tool,
+// and the Credential Manager on Windows.
+//
+// Store the token once, then use it for the requests:
+//
+//	err := auth.SetToken("default", token)
+//	...
+//	token, err := auth.Token("default")
+//	client := paste.NewClient(paste.Token(token))
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// service is the name the tokens are stored under.
+const service = "paste.run"
+
+// ErrNotFound is returned by Token and DeleteToken if the account has no token.
+var ErrNotFound = errors.New("token not found in keyring")
+
+// SetToken stores the token of the account, replacing its current token.
+// The account is a name for the token, such as "default" or a profile name.
+func SetToken(account, token string) error {
+	if account == "" || strings.ContainsAny(account, "\"\\\r\n") {
+		return errors.New("invalid account name")
+	}
+	if token == "" || strings.ContainsAny(token, "\"\\\r\n") {
+		return errors.New("invalid token")
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		// Use the interactive mode so the token is not in the process arguments.
+		return run(exec.Command("security", "-i"),
+			`add-generic-password -U -s "`+service+`" -a "`+account+`" -w "`+token+`"`+"\n")
+	case "windows":
+		return run(powershell(`$v.Add((New-Object Windows.Security.Credentials.PasswordCredential($s, $a, [Console]::In.ReadLine())))`, account),
+			token+"\n")
+	default:
+		return run(exec.Command("secret-tool", "store", "--label=paste.run token "+account,
+			"service", service, "account", account), token)
+	}
+}
+
+// Token returns the token of the account, or ErrNotFound.
+func Token(account string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "windows":
+		cmd = powershell(`$c = $v.Retrieve($s, $a); $c.RetrievePassword(); $c.Password`, account)
+	default:
+		cmd = exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	}
+	out, err := cmd.Output()
+	if notFound(err) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", commandError(err)
+	}
+	token := strings.TrimRight(string(out), "\r\n")
+	if token == "" {
+		return "", ErrNotFound
+	}
+	return token, nil
+}
+
+// DeleteToken removes the token of the account.
+func DeleteToken(account string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "delete-generic-password", "-s", service, "-a", account)
+	case "windows":
+		cmd = powershell(`$v.Remove($v.Retrieve($s, $a))`, account)
+	default:
+		// secret-tool clear succeeds if there is nothing to remove.
+		if _, err := Token(account); err != nil {
+			return err
+		}
+		cmd = exec.Command("secret-tool", "clear", "service", service, "account", account)
+	}
+	err := cmd.Run()
+	if notFound(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return commandError(err)
+	}
+	return nil
+}
+
+// powershell returns a command running script with the Windows PasswordVault
+// in $v, the service in $s and the account in $a.
+// The script exits with code 44 if the credential is not found.
+func powershell(script, account string) *exec.Cmd {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command",
+		`$ErrorActionPreference = 'Stop'; `+
+			`[void][Windows.Security.Credentials.PasswordVault, Windows.Security.Credentials, ContentType = WindowsRuntime]; `+
+			`$v = New-Object Windows.Security.Credentials.PasswordVault; $s = '`+service+`'; $a = $env:PASTE_ACCOUNT; `+
+			`try { `+script+` } catch [System.Runtime.InteropServices.COMException] { exit 44 }`)
+	// Pass the account in the environment, not quoted in the script.
+	cmd.Env = append(os.Environ(), "PASTE_ACCOUNT="+account)
+	return cmd
+}
+
+// notFound reports if the command failed because the token was not found:
+// exit code 44 for security and the PowerShell scripts, 1 for secret-tool.
+func notFound(err error) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	code := exitErr.ExitCode()
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		return code == 44
+	}
+	return code == 1 && len(bytes.TrimSpace(exitErr.Stderr)) == 0
+}
+
+func run(cmd *exec.Cmd, stdin string) error {
+	cmd.Stdin = strings.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return errors.New("keyring: " + msg)
+		}
+		return commandError(err)
+	}
+	return nil
+}
+
+func commandError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if msg := strings.TrimSpace(string(exitErr.Stderr)); msg != "" {
+			return errors.New("keyring: " + msg)
+		}
+	}
+	return errors.New("keyring: " + err.Error())
+}
@@ -0,0 +1,89 @@
+package paste
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strconv"
+)
+
+// BatchError is returned along with the results by InfoBatch,
+// when getting some of the pastes failed.
+type BatchError struct {
+	Errors map[string]string // Error message by paste ID
+}
+
+func (e *BatchError) Error() string {
+	ids := make([]string, 0, len(e.Errors))
+	for id := range e.Errors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	msg := strconv.Itoa(len(ids)) + " pastes failed"
+	for _, id := range ids {
+		msg += "; " + id + ": " + e.Errors[id]
+	}
+	return msg
+}
+
+func infoBatch(ids []string, req *request, options ...Option) (map[string]PasteInfo, error) {
+	req.apply(options)
+
+	for i, paste := range ids {
+		id, err := pasteID(paste)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	body, err := json.Marshal(struct {
+		IDs []string `json:"ids"`
+	}{ids})
+	if err != nil {
+		return nil, err
+	}
+	hr, err := req.newRequest("POST", req.endpoint("info"), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	hr.Header.Set("Content-Type", "application/json")
+	hr.Header.Set("Accept", "application/json")
+
+	resp, err := req.do(hr)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		result, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, transportError(hr, err)
+		}
+		return nil, apiError(resp, result)
+	}
+
+	var x struct {
+		Results map[string]PasteInfo `json:"results"`
+		Errors  map[string]string    `json:"errors"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&x)
+	if err != nil {
+		return nil, err
+	}
+	if x.Results == nil {
+		x.Results = map[string]PasteInfo{}
+	}
+	if len(x.Errors) != 0 {
+		return x.Results, &BatchError{x.Errors}
+	}
+	return x.Results, nil
+}
+
+// InfoBatch gets the information of many pastes in one request,
+// the PasteInfo Content is nil. Pastes that don't exist are not in the map.
+// If some pastes failed, the results are returned with a *BatchError.
+func InfoBatch(ids []string, options ...Option) (map[string]PasteInfo, error) {
+	return infoBatch(append([]string(nil), ids...), &request{}, options...)
+}
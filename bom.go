@@ -0,0 +1,109 @@
+package paste
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var (
+	bomUTF8    = []byte{0xef, 0xbb, 0xbf}
+	bomUTF16LE = []byte{0xff, 0xfe}
+	bomUTF16BE = []byte{0xfe, 0xff}
+)
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// stripBOM removes the byte order mark from the content.
+func stripBOM(info PasteInfo) PasteInfo {
+	br := bufio.NewReader(info.Content)
+	start, _ := br.Peek(3)
+	switch {
+	case bytes.HasPrefix(start, bomUTF8):
+		br.Discard(len(bomUTF8))
+		if info.Size >= 0 {
+			info.Size -= int64(len(bomUTF8))
+		}
+	case bytes.HasPrefix(start, bomUTF16LE):
+		br.Discard(len(bomUTF16LE))
+		info.Content = readCloser{&utf16Reader{r: br}, info.Content}
+		info.Size = -1
+		return info
+	case bytes.HasPrefix(start, bomUTF16BE):
+		br.Discard(len(bomUTF16BE))
+		info.Content = readCloser{&utf16Reader{r: br, bigEndian: true}, info.Content}
+		info.Size = -1
+		return info
+	}
+	info.Content = readCloser{br, info.Content}
+	return info
+}
+
+// utf16Reader converts UTF-16 to UTF-8.
+type utf16Reader struct {
+	r         *bufio.Reader
+	bigEndian bool
+	buf       []byte // Converted bytes not read yet.
+	err       error  // Returned once buf is read.
+}
+
+func (ur *utf16Reader) readUnit() (rune, error) {
+	var b [2]byte
+	_, err := io.ReadFull(ur.r, b[:])
+	if err != nil {
+		return 0, err
+	}
+	return ur.unit(b[:]), nil
+}
+
+func (ur *utf16Reader) unit(b []byte) rune {
+	if ur.bigEndian {
+		return rune(b[0])<<8 | rune(b[1])
+	}
+	return rune(b[1])<<8 | rune(b[0])
+}
+
+func (ur *utf16Reader) Read(p []byte) (int, error) {
+	for len(ur.buf) < len(p) && ur.err == nil {
+		r, err := ur.readUnit()
+		if err == io.ErrUnexpectedEOF {
+			r, err = utf8.RuneError, nil
+		}
+		if err != nil {
+			ur.err = err
+			break
+		}
+		if utf16.IsSurrogate(r) {
+			// A high surrogate pairs with a following low surrogate,
+			// else it is invalid and the following unit is read next.
+			b, err := ur.r.Peek(2)
+			if err != nil && err != io.EOF {
+				ur.err = err
+			}
+			r2 := utf8.RuneError
+			if len(b) == 2 {
+				r2 = ur.unit(b)
+			}
+			r = utf16.DecodeRune(r, r2)
+			if r != utf8.RuneError {
+				ur.r.Discard(2)
+			}
+		}
+		var enc [utf8.UTFMax]byte
+		ur.buf = append(ur.buf, enc[:utf8.EncodeRune(enc[:], r)]...)
+		if ur.r.Buffered() == 0 && len(ur.buf) != 0 {
+			break // Don't block with converted bytes to return.
+		}
+	}
+	if len(ur.buf) == 0 && ur.err != nil {
+		return 0, ur.err
+	}
+	n := copy(p, ur.buf)
+	ur.buf = ur.buf[n:]
+	return n, nil
+}
@@ -0,0 +1,80 @@
+package paste
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestUTF16Reader(t *testing.T) {
+	tests := []struct {
+		units []uint16
+		want  string
+	}{
+		{nil, ""},
+		{utf16.Encode([]rune("héllo")), "héllo"},
+		{utf16.Encode([]rune("a😀b")), "a😀b"},
+		// Unpaired surrogates are replaced, and the next unit is kept.
+		{[]uint16{'a', 0xd83d, 'b'}, "a�b"},
+		{[]uint16{'a', 0xde00, 'b'}, "a�b"},
+		{[]uint16{0xd83d, 0xd83d, 0xde00}, "�😀"},
+		{[]uint16{'a', 0xd83d}, "a�"},
+	}
+	for _, test := range tests {
+		for _, bigEndian := range []bool{false, true} {
+			var b []byte
+			for _, u := range test.units {
+				if bigEndian {
+					b = append(b, byte(u>>8), byte(u))
+				} else {
+					b = append(b, byte(u), byte(u>>8))
+				}
+			}
+			ur := &utf16Reader{r: bufio.NewReader(bytes.NewReader(b)), bigEndian: bigEndian}
+			got, err := ioutil.ReadAll(ur)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != test.want {
+				t.Errorf("%x (big endian %v) = %q, want %q", test.units, bigEndian, got, test.want)
+			}
+		}
+	}
+}
+
+// failingReader returns err once its content is read.
+type failingReader struct {
+	r   io.Reader
+	err error
+}
+
+func (fr *failingReader) Read(p []byte) (int, error) {
+	n, err := fr.r.Read(p)
+	if err == io.EOF {
+		err = fr.err
+	}
+	return n, err
+}
+
+func TestUTF16ReaderError(t *testing.T) {
+	errFail := errors.New("connection reset")
+	for _, test := range []struct {
+		content []byte
+		want    string
+	}{
+		{[]byte{'a', 0, 'b', 0}, "ab"},
+		// The error is in the Peek after a high surrogate.
+		{[]byte{'a', 0, 'b', 0, 0x3d, 0xd8}, "ab\ufffd"},
+	} {
+		fr := &failingReader{bytes.NewReader(test.content), errFail}
+		ur := &utf16Reader{r: bufio.NewReader(fr)}
+		got, err := ioutil.ReadAll(ur)
+		if string(got) != test.want || err != errFail {
+			t.Errorf("%x: got %q, %v; want %q, %v", test.content, got, err, test.want, errFail)
+		}
+	}
+}
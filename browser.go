@@ -0,0 +1,35 @@
+package paste
+
+import (
+	"errors"
+	"net/url"
+	"os/exec"
+	"runtime"
+)
+
+// OpenInBrowser opens the paste URL in the default web browser.
+// It returns once the browser is started.
+func OpenInBrowser(pasteURL string) error {
+	u, err := url.Parse(pasteURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("not a http or https URL")
+	}
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", pasteURL)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", pasteURL)
+	default:
+		cmd = exec.Command("xdg-open", pasteURL)
+	}
+	err = cmd.Start()
+	if err != nil {
+		return err
+	}
+	go cmd.Wait() // Release the process resources.
+	return nil
+}
@@ -0,0 +1,82 @@
+package paste
+
+import (
+	"sync"
+	"time"
+)
+
+// retryBudgetWindow is the number of seconds the budget is kept for.
+const retryBudgetWindow = 10
+
+type retryBudgetBucket struct {
+	sec      int64
+	requests int
+	failures int
+	retries  int
+}
+
+// retryBudget is a token-based retry budget.
+// Every request deposits ratio tokens and every retry withdraws one,
+// over a sliding window of retryBudgetWindow seconds.
+type retryBudget struct {
+	mu        sync.Mutex
+	ratio     float64
+	minPerSec int
+	buckets   [retryBudgetWindow]retryBudgetBucket
+}
+
+// bucket returns the current bucket; must hold mu.
+func (b *retryBudget) bucket(now time.Time) *retryBudgetBucket {
+	sec := now.Unix()
+	bk := &b.buckets[sec%retryBudgetWindow]
+	if bk.sec != sec {
+		*bk = retryBudgetBucket{sec: sec}
+	}
+	return bk
+}
+
+// balance returns the tokens available; must hold mu.
+func (b *retryBudget) balance(now time.Time) float64 {
+	sec := now.Unix()
+	requests, failures, retries := 0, 0, 0
+	for _, bk := range b.buckets {
+		if bk.sec > sec-retryBudgetWindow {
+			requests += bk.requests
+			failures += bk.failures
+			retries += bk.retries
+		}
+	}
+	// Failed requests don't earn retries.
+	return float64(b.minPerSec*retryBudgetWindow) +
+		b.ratio*float64(requests-failures) - float64(retries)
+}
+
+// record a completed request.
+func (b *retryBudget) record(ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	bk := b.bucket(time.Now())
+	bk.requests++
+	if !ok {
+		bk.failures++
+	}
+}
+
+// allow withdraws a token for a retry, returns false if none are left.
+func (b *retryBudget) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	if b.balance(now) < 1 {
+		return false
+	}
+	b.bucket(now).retries++
+	return true
+}
+
+// available returns the number of retries currently allowed.
+func (b *retryBudget) available() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.balance(time.Now())
+}
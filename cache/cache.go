@@ -0,0 +1,213 @@
+// Package cache implements a persistent on-disk cache for paste.Get.
+//
+// Pastes are cached by their URL and ETag. A repeat Get revalidates
+// the paste with a conditional request, and the content is read from
+// the disk if the paste was not modified.
+//
+// The cache stores the content as sent by the server, so an encrypted
+// paste is stored encrypted, and the Get options, such as DecryptWithKey,
+// RawEncoding and StripBOM, apply to the cached content on every Get.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"paste.run"
+)
+
+// Cache is a disk cache of pastes.
+// A Cache is safe for concurrent use, also by several processes.
+type Cache struct {
+	dir string
+
+	// Client gets the pastes, paste.Get is used if nil.
+	Client *paste.Client
+}
+
+// New returns a Cache storing the pastes in the directory dir,
+// which is created if it doesn't exist.
+func New(dir string) (*Cache, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// entry is the cached response of a paste request.
+type entry struct {
+	ETag   string      `json:"etag"`
+	Header http.Header `json:"header"`
+}
+
+func (c *Cache) get(pasteURL string, options ...paste.Option) (paste.PasteInfo, error) {
+	if c.Client != nil {
+		return c.Client.Get(pasteURL, options...)
+	}
+	return paste.Get(pasteURL, options...)
+}
+
+// name returns the file name of the cached response to the request URL,
+// which has the base URL and the paste ID.
+func (c *Cache) name(requestURL *url.URL) string {
+	sum := sha256.Sum256([]byte(requestURL.String()))
+	return filepath.Join(c.dir, idPrefix(path.Base(requestURL.Path))+hex.EncodeToString(sum[:16]))
+}
+
+// idPrefix returns the file name prefix of the cached responses of a paste ID.
+func idPrefix(id string) string {
+	return hex.EncodeToString([]byte(id)) + "-"
+}
+
+// Get gets a paste like paste.Get, from the cache if not modified.
+// Pastes without an ETag are not cached, and neither are Gets with
+// their own IfNoneMatch or IfModifiedSince condition.
+func (c *Cache) Get(pasteURL string, options ...paste.Option) (paste.PasteInfo, error) {
+	g := &cachedGet{c: c}
+	defer g.closeCached()
+	options = append(options[:len(options):len(options)], paste.OnRequest(g.onRequest), paste.OnResponse(g.onResponse))
+	return c.get(pasteURL, options...)
+}
+
+// cachedGet is the state of a Get through the cache.
+type cachedGet struct {
+	c      *Cache
+	name   string   // Of the cached response, empty if not cacheable.
+	cached entry    // Revalidated entry.
+	data   *os.File // Content of the revalidated entry, nil if none.
+}
+
+func (g *cachedGet) onRequest(hr *http.Request) {
+	g.closeCached()
+	g.name = ""
+	if hr.Method != "GET" || hr.Header.Get("Range") != "" ||
+		hr.Header.Get("If-None-Match") != "" || hr.Header.Get("If-Modified-Since") != "" {
+		return
+	}
+	g.name = g.c.name(hr.URL)
+
+	b, err := ioutil.ReadFile(g.name + ".json")
+	if err != nil || json.Unmarshal(b, &g.cached) != nil || g.cached.ETag == "" {
+		return
+	}
+	g.data, err = os.Open(g.name + ".data")
+	if err != nil {
+		return
+	}
+	hr.Header.Set("If-None-Match", g.cached.ETag)
+}
+
+func (g *cachedGet) onResponse(resp *http.Response) {
+	switch {
+	case g.name == "":
+	case resp.StatusCode == 304 && g.data != nil:
+		// Answer with the cached response, for the Get options to apply to it.
+		resp.Body.Close()
+		resp.StatusCode = 200
+		resp.Status = "200 OK"
+		resp.Header = g.cached.Header.Clone()
+		resp.ContentLength = -1
+		if fi, err := g.data.Stat(); err == nil {
+			resp.ContentLength = fi.Size()
+		}
+		resp.Body = g.data
+		g.data = nil
+	case resp.StatusCode == 200 && resp.Header.Get("ETag") != "":
+		f, err := ioutil.TempFile(g.c.dir, ".tmp-*")
+		if err != nil {
+			return
+		}
+		resp.Body = &storeBody{resp.Body, f, g.name, entry{resp.Header.Get("ETag"), resp.Header.Clone()}, false}
+	}
+}
+
+// closeCached closes the content of the revalidated entry if not used.
+func (g *cachedGet) closeCached() {
+	if g.data != nil {
+		g.data.Close()
+		g.data = nil
+	}
+}
+
+// storeBody copies a response body to a temporary file,
+// and stores it in the cache when the body is read to the end.
+type storeBody struct {
+	body  io.ReadCloser
+	f     *os.File
+	name  string
+	entry entry
+	done  bool
+}
+
+func (b *storeBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if n > 0 && !b.done {
+		if _, werr := b.f.Write(p[:n]); werr != nil {
+			b.discard()
+		}
+	}
+	if err == io.EOF && !b.done {
+		b.store()
+	}
+	return n, err
+}
+
+func (b *storeBody) Close() error {
+	b.discard()
+	return b.body.Close()
+}
+
+// discard removes the temporary file, without storing it.
+func (b *storeBody) discard() {
+	if b.done {
+		return
+	}
+	b.done = true
+	b.f.Close()
+	os.Remove(b.f.Name())
+}
+
+func (b *storeBody) store() {
+	b.done = true
+	defer os.Remove(b.f.Name())
+	if b.f.Close() != nil {
+		return
+	}
+	data, err := json.Marshal(b.entry)
+	if err != nil {
+		return
+	}
+	// Remove the old entry first, so a failure can't pair it with the new content.
+	os.Remove(b.name + ".json")
+	if os.Rename(b.f.Name(), b.name+".data") != nil {
+		return
+	}
+	ioutil.WriteFile(b.name+".json", data, 0600)
+}
+
+// Remove removes a paste from the cache.
+func (c *Cache) Remove(pasteURL string) error {
+	pasteURL = strings.SplitN(pasteURL, "#", 2)[0]
+	id := pasteURL[strings.LastIndexByte(pasteURL, '/')+1:]
+	names, err := filepath.Glob(filepath.Join(c.dir, idPrefix(id)+"*"))
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		err = os.Remove(name)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
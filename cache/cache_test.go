@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"paste.run"
+	"paste.run/crypto"
+	"paste.run/pastetest"
+)
+
+// newCache returns a Cache of the server in a temporary directory,
+// and the count of its not modified responses.
+func newCache(t *testing.T, srv *pastetest.Server) (*Cache, *int) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "paste-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notModified := new(int)
+	c.Client = paste.NewClient(paste.NoConfig(), paste.NoEnv(), paste.BaseURL(srv.URL),
+		paste.OnResponse(func(resp *http.Response) {
+			if resp.StatusCode == 304 {
+				*notModified++
+			}
+		}))
+	return c, notModified
+}
+
+func read(t *testing.T, c *Cache, pasteURL string, options ...paste.Option) string {
+	t.Helper()
+	info, err := c.Get(pasteURL, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer info.Content.Close()
+	b, err := ioutil.ReadAll(info.Content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestGetRevalidates(t *testing.T) {
+	srv := pastetest.NewServer()
+	defer srv.Close()
+	pasteURL, err := srv.Client.UploadString("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, notModified := newCache(t, srv)
+	defer os.RemoveAll(c.dir)
+
+	for i := 0; i < 3; i++ {
+		if got := read(t, c, pasteURL); got != "hello" {
+			t.Fatalf("Get %d = %q, want %q", i, got, "hello")
+		}
+	}
+	if *notModified != 2 {
+		t.Errorf("got %d not modified responses, want 2", *notModified)
+	}
+}
+
+func TestGetAppliesOptions(t *testing.T) {
+	srv := pastetest.NewServer()
+	defer srv.Close()
+	pasteURL, err := srv.Client.UploadString("\xef\xbb\xbfhello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ := newCache(t, srv)
+	defer os.RemoveAll(c.dir)
+
+	if got := read(t, c, pasteURL, paste.StripBOM()); got != "hello" {
+		t.Errorf("Get with StripBOM = %q, want %q", got, "hello")
+	}
+	if got := read(t, c, pasteURL); got != "\xef\xbb\xbfhello" {
+		t.Errorf("cached Get = %q, want the BOM", got)
+	}
+	if got := read(t, c, pasteURL, paste.StripBOM()); got != "hello" {
+		t.Errorf("cached Get with StripBOM = %q, want %q", got, "hello")
+	}
+}
+
+func TestGetStoresEncrypted(t *testing.T) {
+	srv := pastetest.NewServer()
+	defer srv.Close()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const secret = "secret content"
+	pasteURL, err := srv.Client.UploadString(secret, paste.EncryptWithKey(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(pasteURL, "#") {
+		t.Fatalf("paste URL %q has no key", pasteURL)
+	}
+	c, _ := newCache(t, srv)
+	defer os.RemoveAll(c.dir)
+
+	for i := 0; i < 2; i++ {
+		if got := read(t, c, pasteURL); got != secret {
+			t.Fatalf("Get %d = %q, want %q", i, got, secret)
+		}
+	}
+	names, err := filepath.Glob(filepath.Join(c.dir, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) == 0 {
+		t.Fatal("nothing cached")
+	}
+	for _, name := range names {
+		b, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(b, []byte(secret)) {
+			t.Errorf("%s has the plaintext", name)
+		}
+	}
+}
+
+func TestRemove(t *testing.T) {
+	srv := pastetest.NewServer()
+	defer srv.Close()
+	pasteURL, err := srv.Client.UploadString("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ := newCache(t, srv)
+	defer os.RemoveAll(c.dir)
+	read(t, c, pasteURL)
+
+	err = c.Remove(pasteURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, _ := filepath.Glob(filepath.Join(c.dir, "*"))
+	if len(names) != 0 {
+		t.Errorf("files left after Remove: %v", names)
+	}
+}
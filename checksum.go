@@ -0,0 +1,56 @@
+package paste
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// VerifyChecksum makes reading the content from Get fail with ErrChecksum
+// if it doesn't match the Content-SHA256 or Digest header of the server.
+// On upload, the SHA-256 of each file is sent for the server to check.
+func VerifyChecksum() Option {
+	return func(req *request) {
+		req.verifyChecksum = true
+	}
+}
+
+// contentSHA256 returns the SHA-256 of the content from the
+// Content-SHA256 header in hex, or the Digest header sha-256 in base64.
+func contentSHA256(h http.Header) []byte {
+	if sum, err := hex.DecodeString(h.Get("Content-SHA256")); err == nil && len(sum) == sha256.Size {
+		return sum
+	}
+	for _, digest := range strings.Split(h.Get("Digest"), ",") {
+		i := strings.IndexByte(digest, '=')
+		if i == -1 || !strings.EqualFold(strings.TrimSpace(digest[:i]), "sha-256") {
+			continue
+		}
+		sum, err := base64.StdEncoding.DecodeString(strings.TrimSpace(digest[i+1:]))
+		if err == nil && len(sum) == sha256.Size {
+			return sum
+		}
+	}
+	return nil
+}
+
+// checksumReader returns ErrChecksum at EOF if the content doesn't match.
+type checksumReader struct {
+	r    io.Reader
+	h    hash.Hash
+	want []byte
+}
+
+func (cr *checksumReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.h.Write(p[:n])
+	if err == io.EOF && !bytes.Equal(cr.h.Sum(nil), cr.want) {
+		return n, ErrChecksum
+	}
+	return n, err
+}
@@ -0,0 +1,48 @@
+package paste
+
+import (
+	"errors"
+	"io"
+)
+
+func getChunks(paste string, chunkSize int, fn func(chunk []byte) error, req *request, options ...Option) (PasteInfo, error) {
+	if chunkSize <= 0 {
+		return PasteInfo{}, errors.New("invalid chunk size")
+	}
+	info, err := get(paste, req, options...)
+	if err != nil {
+		return PasteInfo{}, err
+	}
+	content := info.Content
+	defer content.Close()
+	info.Content = nil
+
+	buf := make([]byte, chunkSize)
+	for {
+		if req.ctx != nil {
+			if err := req.ctx.Err(); err != nil {
+				return PasteInfo{}, err
+			}
+		}
+		n, err := io.ReadFull(content, buf)
+		if n > 0 {
+			if err := fn(buf[:n]); err != nil {
+				return PasteInfo{}, err
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return info, nil
+		}
+		if err != nil {
+			return PasteInfo{}, err
+		}
+	}
+}
+
+// GetChunks gets a paste and calls fn with each chunkSize chunk of its content,
+// the last chunk can be smaller. The chunk is only valid during the call.
+// If fn returns an error, GetChunks stops and returns that error.
+// The returned PasteInfo has a nil Content.
+func GetChunks(paste string, chunkSize int, fn func(chunk []byte) error, options ...Option) (PasteInfo, error) {
+	return getChunks(paste, chunkSize, fn, &request{}, options...)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// clipboardCommands returns the commands to copy to and paste from
+// the system clipboard, the first one found in PATH is used.
+func clipboardCommands() (copyCmds, pasteCmds [][]string) {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}, [][]string{{"pbpaste"}}
+	case "windows":
+		return [][]string{{"clip.exe"}},
+			[][]string{{"powershell.exe", "-NoProfile", "-Command", "Get-Clipboard -Raw"}}
+	}
+	var wayland [][]string
+	var wlPaste [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		wayland = [][]string{{"wl-copy"}}
+		wlPaste = [][]string{{"wl-paste", "--no-newline"}}
+	}
+	return append(wayland, []string{"xclip", "-selection", "clipboard"},
+			[]string{"xsel", "--clipboard", "--input"}),
+		append(wlPaste, []string{"xclip", "-selection", "clipboard", "-o"},
+			[]string{"xsel", "--clipboard", "--output"})
+}
+
+func findCommand(cmds [][]string) (*exec.Cmd, error) {
+	for _, args := range cmds {
+		if _, err := exec.LookPath(args[0]); err == nil {
+			return exec.Command(args[0], args[1:]...), nil
+		}
+	}
+	return nil, errors.New("no clipboard command found, such as xclip or wl-copy")
+}
+
+// copyToClipboard puts s on the system clipboard.
+func copyToClipboard(s string) error {
+	copyCmds, _ := clipboardCommands()
+	cmd, err := findCommand(copyCmds)
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = strings.NewReader(s)
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// clipboardReader returns a reader of the system clipboard contents.
+// Closing it waits for the clipboard command.
+func clipboardReader() (io.ReadCloser, error) {
+	_, pasteCmds := clipboardCommands()
+	cmd, err := findCommand(pasteCmds)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stderr = os.Stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	err = cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+	return &cmdReader{out, cmd}, nil
+}
+
+type cmdReader struct {
+	io.ReadCloser
+	cmd *exec.Cmd
+}
+
+func (r *cmdReader) Close() error {
+	r.ReadCloser.Close()
+	return r.cmd.Wait()
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+const bashCompletion = `_paste() {
+	local IFS=$'\n'
+	COMPREPLY=($(paste __complete "${COMP_WORDS[@]:1:COMP_CWORD}"))
+	if [ ${#COMPREPLY[@]} -eq 0 ]; then
+		COMPREPLY=($(compgen -f -- "${COMP_WORDS[COMP_CWORD]}"))
+	fi
+}
+complete -F _paste paste
+`
+
+const zshCompletion = `#compdef paste
+_paste() {
+	local -a candidates
+	candidates=("${(@f)$(paste __complete "${words[@]:1:$((CURRENT-1))}")}")
+	if [[ -n "${candidates[1]}" ]]; then
+		compadd -a candidates
+	else
+		_files
+	fi
+}
+compdef _paste paste
+`
+
+const fishCompletion = `complete -c paste -a '(paste __complete (commandline -opc)[2..-1] (commandline -ct))'
+`
+
+func completion(fs *flag.FlagSet) func() error {
+	return func() error {
+		switch fs.Arg(0) {
+		case "bash":
+			fmt.Print(bashCompletion)
+		case "zsh":
+			fmt.Print(zshCompletion)
+		case "fish":
+			fmt.Print(fishCompletion)
+		default:
+			return errors.New("completion shell must be bash, zsh or fish")
+		}
+		return nil
+	}
+}
+
+// complete prints the completions of the last of the words,
+// the arguments after "paste", for the hidden __complete command
+// used by the completion scripts. Nothing is printed to complete file names.
+func complete(words []string) {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	cur := words[len(words)-1]
+	if len(words) == 1 {
+		for _, cmd := range commands {
+			if strings.HasPrefix(cmd.name, cur) {
+				fmt.Println(cmd.name)
+			}
+		}
+		return
+	}
+
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == words[0] {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		return
+	}
+	prev := words[len(words)-2]
+	switch {
+	case prev == "-type" || prev == "--type":
+		c := client()
+		langs, err := c.GetLanguages()
+		if err != nil {
+			return
+		}
+		for _, lang := range langs {
+			if strings.HasPrefix(lang.Name, cur) {
+				fmt.Println(lang.Name)
+			}
+		}
+	case strings.HasPrefix(cur, "-"):
+		fs := newFlagSet(*cmd)
+		cmd.setup(fs)
+		prefix := "-"
+		if strings.HasPrefix(cur, "--") {
+			prefix = "--"
+		}
+		fs.VisitAll(func(f *flag.Flag) {
+			if strings.HasPrefix(prefix+f.Name, cur) {
+				fmt.Println(prefix + f.Name)
+			}
+		})
+	}
+}
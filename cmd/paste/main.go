@@ -0,0 +1,262 @@
+// Command paste uploads and gets pastes on paste.run.
+//
+// Usage:
+//
+//	paste upload [flags] [file...]
+//	paste get [-o path] paste
+//	paste delete paste...
+//	paste list [-limit n] [-after cursor]
+//	paste languages
+//	paste completion bash|zsh|fish
+//
+// The content to upload is read from stdin if no file is given.
+// Defaults such as the token are read from the config file,
+// see paste.LoadConfig, and from the PASTE_TOKEN, PASTE_BASE_URL
+// and PASTE_AUTHOR environment variables, which override it.
+//
+// To enable shell completion, for example with bash:
+//
+//	source <(paste completion bash)
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"paste.run"
+)
+
+type command struct {
+	name  string
+	usage string
+	// setup defines the flags of the command,
+	// and returns the func to run it once the flags are parsed.
+	setup func(fs *flag.FlagSet) func() error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"upload", "[flags] [file...]", upload},
+		{"get", "[-o path] paste", get},
+		{"delete", "paste...", deletePastes},
+		{"list", "[-limit n] [-after cursor]", list},
+		{"languages", "", languages},
+		{"completion", "bash|zsh|fish", completion},
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage:")
+	for _, cmd := range commands {
+		fmt.Fprintln(os.Stderr, "\t"+strings.TrimSpace("paste "+cmd.name+" "+cmd.usage))
+	}
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	if os.Args[1] == "__complete" {
+		complete(os.Args[2:])
+		return
+	}
+	for _, cmd := range commands {
+		if cmd.name == os.Args[1] {
+			fs := newFlagSet(cmd)
+			run := cmd.setup(fs)
+			fs.Parse(os.Args[2:])
+			err := run()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "paste:", paste.FriendlyError(err))
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	usage()
+}
+
+// token and profile are the -token and -profile flags of all the commands.
+var token, profile string
+
+// newFlagSet returns the flag set of a command with the common flags.
+func newFlagSet(cmd command) *flag.FlagSet {
+	fs := flag.NewFlagSet("paste "+cmd.name, flag.ExitOnError)
+	fs.StringVar(&token, "token", "", "API token, overrides the config file")
+	fs.StringVar(&profile, "profile", "", "config file profile, see paste.Profile")
+	return fs
+}
+
+// client returns the client with the config file and environment defaults.
+func client() *paste.Client {
+	options := []paste.Option{paste.UserAgent("paste-cmd/" + paste.Version)}
+	if profile != "" {
+		options = append(options, paste.Profile(profile))
+	}
+	if token != "" {
+		options = append(options, paste.Token(token))
+	}
+	return paste.NewClient(options...)
+}
+
+func upload(fs *flag.FlagSet) func() error {
+	title := fs.String("title", "", "paste title, defaults to the file name")
+	author := fs.String("author", "", "paste author")
+	desc := fs.String("desc", "", "paste description")
+	typ := fs.String("type", "", "paste language, see paste languages")
+	expires := fs.Duration("expires", 0, "expire the paste after the duration")
+	visibility := fs.String("visibility", "", "public, unlisted or private")
+	copyURL := fs.Bool("copy", false, "copy the paste URL to the clipboard")
+	fromClipboard := fs.Bool("from-clipboard", false, "upload the clipboard contents")
+	open := fs.Bool("open", false, "open the paste in the browser")
+	qr := fs.Bool("qr", false, "show the paste URL as a QR code on stderr")
+	qrPNG := fs.String("qr-png", "", "write the paste URL as a QR code PNG image to the file")
+	return func() error {
+		c := client()
+		var options []paste.Option
+		if *title != "" {
+			options = append(options, paste.Title(*title))
+		}
+		if *author != "" {
+			options = append(options, paste.Author(*author))
+		}
+		if *desc != "" {
+			options = append(options, paste.Description(*desc))
+		}
+		if *typ != "" {
+			options = append(options, paste.Type(*typ))
+		}
+		if *expires > 0 {
+			options = append(options, paste.ExpiresIn(*expires))
+		}
+		if *visibility != "" {
+			options = append(options, paste.Visibility(paste.PasteVisibility(*visibility)))
+		}
+
+		var pasteURL string
+		var err error
+		switch {
+		case *fromClipboard:
+			var r io.ReadCloser
+			r, err = clipboardReader()
+			if err != nil {
+				return err
+			}
+			pasteURL, err = c.Upload(r, options...)
+			if cerr := r.Close(); err == nil {
+				err = cerr
+			}
+		case fs.NArg() == 0:
+			pasteURL, err = c.Upload(os.Stdin, options...)
+		case fs.NArg() == 1:
+			pasteURL, err = c.UploadFile(fs.Arg(0), options...)
+		default:
+			pasteURL, err = c.UploadFilePaths(fs.Args(), options...)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println(pasteURL)
+		if *copyURL {
+			err = copyToClipboard(pasteURL)
+			if err != nil {
+				return err
+			}
+		}
+		if *qr {
+			text, err := paste.QRCodeText(pasteURL)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(os.Stderr, text)
+		}
+		if *qrPNG != "" {
+			img, err := paste.QRCode(pasteURL)
+			if err != nil {
+				return err
+			}
+			err = ioutil.WriteFile(*qrPNG, img, 0666)
+			if err != nil {
+				return err
+			}
+		}
+		if *open {
+			return paste.OpenInBrowser(pasteURL)
+		}
+		return nil
+	}
+}
+
+func get(fs *flag.FlagSet) func() error {
+	out := fs.String("o", "", "write the content to the file or directory, instead of stdout")
+	return func() error {
+		if fs.NArg() != 1 {
+			fs.Usage()
+			os.Exit(2)
+		}
+		c := client()
+		if *out != "" {
+			return c.DownloadFile(fs.Arg(0), *out)
+		}
+		info, err := c.Get(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer info.Content.Close()
+		_, err = io.Copy(os.Stdout, info.Content)
+		return err
+	}
+}
+
+func deletePastes(fs *flag.FlagSet) func() error {
+	return func() error {
+		c := client()
+		for _, p := range fs.Args() {
+			err := c.Delete(p)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func list(fs *flag.FlagSet) func() error {
+	limit := fs.Int("limit", 0, "number of pastes to list")
+	after := fs.String("after", "", "list the pastes after the cursor")
+	return func() error {
+		c := client()
+		pl, err := c.ListPastes(paste.Limit(*limit), paste.After(*after))
+		if err != nil {
+			return err
+		}
+		for _, p := range pl.Pastes {
+			fmt.Printf("%s\t%d\t%s\t%s\n", p.ID, p.Size, p.Created.Format("2006-01-02 15:04"), p.Title)
+		}
+		if pl.Next != "" {
+			fmt.Fprintln(os.Stderr, "next page: paste list -after", pl.Next)
+		}
+		return nil
+	}
+}
+
+func languages(fs *flag.FlagSet) func() error {
+	return func() error {
+		c := client()
+		langs, err := c.GetLanguages()
+		if err != nil {
+			return err
+		}
+		for _, lang := range langs {
+			fmt.Println(lang.Name)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,199 @@
+package paste
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ContentEncoding is a compression for uploads and Get.
+type ContentEncoding string
+
+// The content encodings, Zstd needs a codec from RegisterEncoding.
+const (
+	Gzip ContentEncoding = "gzip"
+	Zstd ContentEncoding = "zstd"
+)
+
+// codec compresses and decompresses a content encoding.
+type codec struct {
+	newWriter func(io.Writer) (io.WriteCloser, error)
+	newReader func(io.Reader) (io.ReadCloser, error)
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[ContentEncoding]codec{
+		Gzip: {
+			newWriter: func(w io.Writer) (io.WriteCloser, error) {
+				return gzip.NewWriter(w), nil
+			},
+			newReader: func(r io.Reader) (io.ReadCloser, error) {
+				return gzip.NewReader(r)
+			},
+		},
+	}
+)
+
+// RegisterEncoding registers a codec for a content encoding,
+// such as Zstd with a zstd package:
+//
+//	paste.RegisterEncoding(paste.Zstd,
+//		func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) },
+//		func(r io.Reader) (io.ReadCloser, error) {
+//			d, err := zstd.NewReader(r)
+//			return d.IOReadCloser(), err
+//		})
+//
+// Get accepts the registered encodings when the server supports them.
+func RegisterEncoding(encoding ContentEncoding,
+	newWriter func(io.Writer) (io.WriteCloser, error),
+	newReader func(io.Reader) (io.ReadCloser, error)) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[encoding] = codec{newWriter, newReader}
+}
+
+func lookupCodec(encoding ContentEncoding) (codec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	c, ok := codecs[encoding]
+	return c, ok
+}
+
+// Compress the upload with gzip, unless the content is already compressed.
+func Compress() Option {
+	return Compression(Gzip)
+}
+
+// Compression compresses the upload with the encoding,
+// unless the content is already compressed.
+func Compression(encoding ContentEncoding) Option {
+	return func(req *request) {
+		req.compression = encoding
+	}
+}
+
+// RawEncoding keeps the content from Get compressed as sent by the server,
+// see PasteInfo Encoding. By default the content is decompressed.
+func RawEncoding() Option {
+	return func(req *request) {
+		req.rawEncoding = true
+	}
+}
+
+// acceptEncoding returns the Accept-Encoding for Get, zstd preferred.
+func acceptEncoding() string {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	var encodings []string
+	for encoding := range codecs {
+		if encoding != Zstd {
+			encodings = append(encodings, string(encoding))
+		}
+	}
+	sort.Strings(encodings)
+	if _, ok := codecs[Zstd]; ok {
+		encodings = append([]string{string(Zstd)}, encodings...)
+	}
+	return strings.Join(encodings, ", ")
+}
+
+// compressedMagic are the starts of already compressed content.
+var compressedMagic = [][]byte{
+	{0x1f, 0x8b},                       // gzip
+	{0x28, 0xb5, 0x2f, 0xfd},           // zstd
+	{'P', 'K', 0x03, 0x04},             // zip
+	{0xfd, '7', 'z', 'X', 'Z', 0x00},   // xz
+	{'B', 'Z', 'h'},                    // bzip2
+	{'7', 'z', 0xbc, 0xaf, 0x27, 0x1c}, // 7z
+	{0x89, 'P', 'N', 'G'},              // png
+	{0xff, 0xd8, 0xff},                 // jpeg
+	{'G', 'I', 'F', '8'},               // gif
+}
+
+// isCompressed reports if the content starts like compressed content.
+func isCompressed(start []byte) bool {
+	for _, magic := range compressedMagic {
+		if bytes.HasPrefix(start, magic) {
+			return true
+		}
+	}
+	return false
+}
+
+// uploadEncoding returns the Content-Encoding to upload the parts with,
+// or empty to not compress. The parts are wrapped to peek at the content.
+func (req *request) uploadEncoding(parts []io.Reader) (ContentEncoding, error) {
+	if req.compression == "" || req.encryptKey != nil {
+		return "", nil // Encrypted content doesn't compress.
+	}
+	if _, ok := lookupCodec(req.compression); !ok {
+		return "", errors.New("unsupported Content-Encoding: " + string(req.compression))
+	}
+	encoding := req.compression
+	for i, part := range parts {
+		br := bufio.NewReader(part)
+		parts[i] = br
+		if start, _ := br.Peek(8); isCompressed(start) {
+			encoding = ""
+		}
+	}
+	return encoding, nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// newEncoder returns a writer compressing to w with the encoding.
+func newEncoder(w io.Writer, encoding ContentEncoding) (io.WriteCloser, error) {
+	if encoding == "" {
+		return nopWriteCloser{w}, nil
+	}
+	c, ok := lookupCodec(encoding)
+	if !ok {
+		return nil, errors.New("unsupported Content-Encoding: " + string(encoding))
+	}
+	return c.newWriter(w)
+}
+
+// decodeContent returns body decompressed with the Content-Encoding.
+func decodeContent(body io.ReadCloser, encoding string) (io.ReadCloser, error) {
+	if encoding == "identity" {
+		return body, nil
+	}
+	c, ok := lookupCodec(ContentEncoding(encoding))
+	if !ok {
+		body.Close()
+		return nil, errors.New("unsupported Content-Encoding: " + encoding)
+	}
+	zr, err := c.newReader(body)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	return readCloser{zr, closers{zr, body}}, nil
+}
+
+// closers closes all the closers.
+type closers []io.Closer
+
+func (cs closers) Close() error {
+	var err error
+	for _, c := range cs {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,37 @@
+package paste
+
+import (
+	"net/http"
+	"time"
+)
+
+// IfNoneMatch makes Get and Stat fail with ErrNotModified
+// if the paste still has the ETag, see PasteInfo ETag.
+func IfNoneMatch(etag string) Option {
+	return func(req *request) {
+		req.ifNoneMatch = etag
+	}
+}
+
+// IfModifiedSince makes Get and Stat fail with ErrNotModified
+// if the paste was not modified since t.
+func IfModifiedSince(t time.Time) Option {
+	return func(req *request) {
+		req.ifModifiedSince = t
+	}
+}
+
+// conditional reports if the request has conditions.
+func (req *request) conditional() bool {
+	return req.ifNoneMatch != "" || !req.ifModifiedSince.IsZero()
+}
+
+// setConditions sets the conditional request headers.
+func (req *request) setConditions(hr *http.Request) {
+	if req.ifNoneMatch != "" {
+		hr.Header.Set("If-None-Match", req.ifNoneMatch)
+	}
+	if !req.ifModifiedSince.IsZero() {
+		hr.Header.Set("If-Modified-Since", req.ifModifiedSince.UTC().Format(http.TimeFormat))
+	}
+}
@@ -0,0 +1,246 @@
+package paste
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type configFile struct {
+	Token     string `json:"token,omitempty"`
+	BaseURL   string `json:"base_url,omitempty"`
+	Author    string `json:"author,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	Expires   string `json:"expires,omitempty"` // Duration, such as 24h.
+	Proxy     string `json:"proxy,omitempty"`
+}
+
+// ConfigFilePath returns the default JSON config file path,
+// such as ~/.config/paste.run/config.json
+//
+// Deprecated: NewClient uses the TOML config file, see ConfigPath.
+func ConfigFilePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "paste.run", "config.json"), nil
+}
+
+// FromConfigFile reads the JSON config file at path and returns its options.
+// The config file can have: token, base_url, author, user_agent, expires and proxy.
+// Options passed after the config options override them, for example:
+//
+//	paste.Upload(r, append(configOptions, paste.Author("Chris"))...)
+//
+// Deprecated: Use NewClient, which reads the TOML config file, see LoadConfig.
+func FromConfigFile(path string) ([]Option, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg configFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	err = dec.Decode(&cfg)
+	if err != nil {
+		return nil, fmt.Errorf("paste config %s: %w", path, err)
+	}
+	return cfg.options(path)
+}
+
+// ConfigPath returns the default TOML config file path,
+// such as ~/.config/paste/config.toml
+func ConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "paste", "config.toml"), nil
+}
+
+// LoadConfig reads the TOML config file at ConfigPath and returns its options,
+// or no options if the file doesn't exist. NewClient uses it for its defaults.
+// The config file has the same keys as FromConfigFile, for example:
+//
+//	token = "..."
+//	author = "Chris"
+//	expires = "168h"
+//
+// Named profiles are in profile tables, see Profile.
+func LoadConfig() ([]Option, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return nil, nil // No config directory.
+	}
+	options, err := loadTOMLConfig(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return options, err
+}
+
+func loadTOMLConfig(path string) ([]Option, error) {
+	tables, err := readTOMLConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	for table := range tables {
+		if table != "" && !strings.HasPrefix(table, "profile.") {
+			return nil, fmt.Errorf("paste config %s: unknown table %s", path, table)
+		}
+	}
+	return tomlConfig(path, tables[""])
+}
+
+func readTOMLConfig(path string) (map[string]map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	tables, err := parseTOML(f)
+	if err != nil {
+		return nil, fmt.Errorf("paste config %s: %w", path, err)
+	}
+	return tables, nil
+}
+
+// tomlConfig returns the options of the keys of a config table.
+func tomlConfig(path string, keys map[string]string) ([]Option, error) {
+	var cfg configFile
+	for key, value := range keys {
+		switch key {
+		case "token":
+			cfg.Token = value
+		case "base_url":
+			cfg.BaseURL = value
+		case "author":
+			cfg.Author = value
+		case "user_agent":
+			cfg.UserAgent = value
+		case "expires":
+			cfg.Expires = value
+		case "proxy":
+			cfg.Proxy = value
+		default:
+			return nil, fmt.Errorf("paste config %s: unknown key %s", path, key)
+		}
+	}
+	return cfg.options(path)
+}
+
+// Profile uses the token, base URL and other defaults of a named profile
+// of the config file, see LoadConfig, for example with:
+//
+//	[profile.work]
+//	token = "..."
+//	base_url = "https://paste.example.com"
+//
+// The token and base URL are set together, to the defaults if the profile has none.
+// An unknown profile or invalid config file is returned by the requests.
+func Profile(name string) Option {
+	options, err := loadProfile(name)
+	return func(req *request) {
+		if err != nil {
+			req.err = err
+			return
+		}
+		req.tok = ""
+		req.baseURL = ""
+		for _, opt := range options {
+			opt(req)
+		}
+	}
+}
+
+func loadProfile(name string) ([]Option, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	tables, err := readTOMLConfig(path)
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	keys, ok := tables["profile."+name]
+	if !ok {
+		return nil, errors.New("paste config: unknown profile " + name)
+	}
+	return tomlConfig(path, keys)
+}
+
+// envOptions returns the options of the PASTE_TOKEN, PASTE_BASE_URL
+// and PASTE_AUTHOR environment variables.
+func envOptions() ([]Option, error) {
+	cfg := configFile{
+		Token:   os.Getenv("PASTE_TOKEN"),
+		BaseURL: os.Getenv("PASTE_BASE_URL"),
+		Author:  os.Getenv("PASTE_AUTHOR"),
+	}
+	return cfg.options("environment")
+}
+
+// options checks the config and returns its options.
+func (cfg *configFile) options(path string) ([]Option, error) {
+	if cfg.BaseURL != "" {
+		u, err := url.Parse(cfg.BaseURL)
+		if err == nil && (u.Scheme != "http" && u.Scheme != "https" || u.Host == "") {
+			err = errors.New("base_url must be a http or https URL")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("paste config %s: %w", path, err)
+		}
+	}
+	var expires time.Duration
+	if cfg.Expires != "" {
+		var err error
+		expires, err = time.ParseDuration(cfg.Expires)
+		if err == nil && expires <= 0 {
+			err = errors.New("expires must be positive")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("paste config %s: %w", path, err)
+		}
+	}
+
+	var options []Option
+	if cfg.Token != "" {
+		options = append(options, Token(cfg.Token))
+	}
+	if cfg.BaseURL != "" {
+		options = append(options, BaseURL(cfg.BaseURL))
+	}
+	if cfg.Author != "" {
+		options = append(options, Author(cfg.Author))
+	}
+	if cfg.UserAgent != "" {
+		ua := cfg.UserAgent
+		options = append(options, func(req *request) {
+			req.userAgent = ua
+		})
+	}
+	if expires != 0 {
+		options = append(options, ExpiresIn(expires))
+	}
+	if cfg.Proxy != "" {
+		proxy := Proxy(cfg.Proxy)
+		probe := &request{}
+		proxy(probe)
+		if probe.err != nil {
+			return nil, fmt.Errorf("paste config %s: %w", path, probe.err)
+		}
+		options = append(options, proxy)
+	}
+	return options, nil
+}
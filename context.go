@@ -0,0 +1,23 @@
+package paste
+
+import "context"
+
+type optionsKey struct{}
+
+// ContextWithOptions returns a copy of ctx with options added to it.
+// Requests given the returned Context with the Context option use
+// these options, such as a Token set by a HTTP middleware.
+// Options passed to the request itself take precedence over them.
+func ContextWithOptions(ctx context.Context, options ...Option) context.Context {
+	prev := OptionsFromContext(ctx)
+	all := make([]Option, 0, len(prev)+len(options))
+	all = append(all, prev...)
+	all = append(all, options...)
+	return context.WithValue(ctx, optionsKey{}, all)
+}
+
+// OptionsFromContext returns the options added to ctx by ContextWithOptions.
+func OptionsFromContext(ctx context.Context) []Option {
+	options, _ := ctx.Value(optionsKey{}).([]Option)
+	return options
+}
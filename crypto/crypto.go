@@ -0,0 +1,221 @@
+// Package crypto implements client-side encryption of paste content.
+//
+// The content is encrypted with AES-256-GCM in chunks of 64 KiB,
+// so streams of any size can be encrypted and decrypted
+// without holding them in memory. Each chunk is authenticated,
+// including whether it is the final chunk, so truncated or
+// reordered content fails to decrypt.
+package crypto
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// KeySize is the size of an encryption key in bytes.
+const KeySize = 32
+
+const (
+	chunkSize   = 64 << 10
+	prefixSize  = 7 // Random nonce prefix written before the first chunk.
+	counterSize = 4
+)
+
+var (
+	// ErrInvalidKey is returned for keys not KeySize bytes long.
+	ErrInvalidKey = errors.New("crypto: invalid key size")
+	// ErrDecrypt is returned when the content fails to authenticate,
+	// such as with the wrong key or corrupted content.
+	ErrDecrypt = errors.New("crypto: message authentication failed")
+)
+
+// GenerateKey returns a new random key.
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	_, err := io.ReadFull(rand.Reader, key)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// EncodeKey encodes the key for use in a URL fragment.
+func EncodeKey(key []byte) string {
+	return base64.RawURLEncoding.EncodeToString(key)
+}
+
+// DecodeKey decodes a key encoded with EncodeKey.
+func DecodeKey(s string) ([]byte, error) {
+	key, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// stream holds the state shared by encryption and decryption.
+type stream struct {
+	aead    cipher.AEAD
+	nonce   [12]byte // Prefix, chunk counter and final chunk flag.
+	counter uint32
+	r       *bufio.Reader
+	buf     []byte
+	out     []byte // Output not yet read.
+	header  bool   // Nonce prefix written or read.
+	done    bool
+	err     error
+}
+
+// next sets the nonce for the next chunk.
+func (s *stream) next(final bool) error {
+	binary.BigEndian.PutUint32(s.nonce[prefixSize:], s.counter)
+	s.nonce[prefixSize+counterSize] = 0
+	if final {
+		s.nonce[prefixSize+counterSize] = 1
+	}
+	s.counter++
+	if s.counter == 0 {
+		return errors.New("crypto: content too large")
+	}
+	return nil
+}
+
+// readChunk reads up to n bytes into s.buf and reports if it is the final chunk.
+func (s *stream) readChunk(n int) (final bool, err error) {
+	m, err := io.ReadFull(s.r, s.buf[:n])
+	s.buf = s.buf[:m]
+	switch err {
+	case io.EOF, io.ErrUnexpectedEOF:
+		return true, nil
+	case nil:
+		_, err = s.r.Peek(1)
+		if err == io.EOF {
+			return true, nil
+		}
+		return false, err
+	}
+	return false, err
+}
+
+func (s *stream) read(p []byte, fill func() error) (int, error) {
+	for len(s.out) == 0 {
+		if s.err != nil {
+			return 0, s.err
+		}
+		if s.done {
+			return 0, io.EOF
+		}
+		s.err = fill()
+	}
+	n := copy(p, s.out)
+	s.out = s.out[n:]
+	return n, nil
+}
+
+type encrypter struct {
+	stream
+}
+
+// NewEncrypter returns a reader of the content of r encrypted with key.
+func NewEncrypter(r io.Reader, key []byte) (io.Reader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	e := &encrypter{}
+	e.aead = aead
+	e.r = bufio.NewReader(r)
+	e.buf = make([]byte, 0, chunkSize+aead.Overhead())
+	_, err = io.ReadFull(rand.Reader, e.nonce[:prefixSize])
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *encrypter) Read(p []byte) (int, error) {
+	return e.read(p, e.fill)
+}
+
+func (e *encrypter) fill() error {
+	if !e.header {
+		e.header = true
+		e.out = e.nonce[:prefixSize]
+		return nil
+	}
+	final, err := e.readChunk(chunkSize)
+	if err != nil {
+		return err
+	}
+	err = e.next(final)
+	if err != nil {
+		return err
+	}
+	e.out = e.aead.Seal(e.buf[:0], e.nonce[:], e.buf, nil)
+	e.done = final
+	return nil
+}
+
+type decrypter struct {
+	stream
+}
+
+// NewDecrypter returns a reader of the content of r decrypted with key.
+// Reads return ErrDecrypt if the content fails to authenticate.
+func NewDecrypter(r io.Reader, key []byte) (io.Reader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	d := &decrypter{}
+	d.aead = aead
+	d.r = bufio.NewReader(r)
+	d.buf = make([]byte, 0, chunkSize+aead.Overhead())
+	return d, nil
+}
+
+func (d *decrypter) Read(p []byte) (int, error) {
+	return d.read(p, d.fill)
+}
+
+func (d *decrypter) fill() error {
+	if !d.header {
+		d.header = true
+		_, err := io.ReadFull(d.r, d.nonce[:prefixSize])
+		if err != nil {
+			return ErrDecrypt
+		}
+	}
+	final, err := d.readChunk(chunkSize + d.aead.Overhead())
+	if err != nil {
+		return err
+	}
+	err = d.next(final)
+	if err != nil {
+		return err
+	}
+	out, err := d.aead.Open(d.buf[:0], d.nonce[:], d.buf, nil)
+	if err != nil {
+		return ErrDecrypt
+	}
+	d.out = out
+	d.done = final
+	return nil
+}
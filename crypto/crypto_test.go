@@ -0,0 +1,132 @@
+package crypto
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func encrypt(t *testing.T, key, plaintext []byte) []byte {
+	t.Helper()
+	r, err := NewEncrypter(bytes.NewReader(plaintext), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ciphertext
+}
+
+func decrypt(key, ciphertext []byte) ([]byte, error) {
+	r, err := NewDecrypter(bytes.NewReader(ciphertext), key)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(r)
+}
+
+func newKey(t *testing.T) []byte {
+	t.Helper()
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestRoundTrip(t *testing.T) {
+	key := newKey(t)
+	for _, size := range []int{0, 1, 100, chunkSize - 1, chunkSize, chunkSize + 1, 3*chunkSize + 7} {
+		plaintext := make([]byte, size)
+		for i := range plaintext {
+			plaintext[i] = byte(i * 7)
+		}
+		ciphertext := encrypt(t, key, plaintext)
+		got, err := decrypt(key, ciphertext)
+		if err != nil {
+			t.Errorf("size %d: %v", size, err)
+			continue
+		}
+		if !bytes.Equal(got, plaintext) {
+			t.Errorf("size %d: decrypted content differs", size)
+		}
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	key := newKey(t)
+	ciphertext := encrypt(t, key, nil)
+	if len(ciphertext) == 0 {
+		t.Fatal("empty content encrypted to nothing")
+	}
+	got, err := decrypt(key, ciphertext)
+	if err != nil || len(got) != 0 {
+		t.Errorf("decrypt = %q, %v; want empty content", got, err)
+	}
+	// Empty ciphertext has no final chunk.
+	_, err = decrypt(key, nil)
+	if err != ErrDecrypt {
+		t.Errorf("decrypt of empty ciphertext: got %v, want ErrDecrypt", err)
+	}
+}
+
+func TestTruncated(t *testing.T) {
+	key := newKey(t)
+	ciphertext := encrypt(t, key, make([]byte, 2*chunkSize+100))
+	overhead := 16
+	for _, n := range []int{
+		prefixSize - 1,                    // In the nonce prefix.
+		prefixSize,                        // No chunks.
+		prefixSize + chunkSize + overhead, // At a chunk boundary.
+		prefixSize + chunkSize + 10,       // In a chunk.
+		len(ciphertext) - 1,               // In the final chunk.
+	} {
+		_, err := decrypt(key, ciphertext[:n])
+		if err != ErrDecrypt {
+			t.Errorf("truncated to %d bytes: got %v, want ErrDecrypt", n, err)
+		}
+	}
+}
+
+func TestReordered(t *testing.T) {
+	key := newKey(t)
+	ciphertext := encrypt(t, key, make([]byte, 3*chunkSize))
+	chunk := chunkSize + 16
+	first := ciphertext[prefixSize : prefixSize+chunk]
+	second := ciphertext[prefixSize+chunk : prefixSize+2*chunk]
+
+	var reordered []byte
+	reordered = append(reordered, ciphertext[:prefixSize]...)
+	reordered = append(reordered, second...)
+	reordered = append(reordered, first...)
+	reordered = append(reordered, ciphertext[prefixSize+2*chunk:]...)
+	_, err := decrypt(key, reordered)
+	if err != ErrDecrypt {
+		t.Errorf("reordered chunks: got %v, want ErrDecrypt", err)
+	}
+}
+
+func TestWrongKey(t *testing.T) {
+	ciphertext := encrypt(t, newKey(t), []byte("hello"))
+	_, err := decrypt(newKey(t), ciphertext)
+	if err != ErrDecrypt {
+		t.Errorf("wrong key: got %v, want ErrDecrypt", err)
+	}
+	_, err = NewDecrypter(bytes.NewReader(ciphertext), []byte("short"))
+	if err != ErrInvalidKey {
+		t.Errorf("short key: got %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestKeyEncoding(t *testing.T) {
+	key := newKey(t)
+	got, err := DecodeKey(EncodeKey(key))
+	if err != nil || !bytes.Equal(got, key) {
+		t.Errorf("DecodeKey(EncodeKey(key)) = %x, %v; want %x", got, err, key)
+	}
+	if _, err := DecodeKey("c2hvcnQ"); err != ErrInvalidKey {
+		t.Errorf("DecodeKey of a short key: got %v, want ErrInvalidKey", err)
+	}
+}
@@ -0,0 +1,34 @@
+package paste
+
+import "time"
+
+// EffectiveDeadline returns the deadline a request with the options
+// would have if it started now, or false if it has no deadline.
+//
+// The Context deadline, the Timeout option and the HTTPClient's Timeout
+// all apply to a request, so the earliest of them is the deadline.
+// Each covers the whole request, including reading the response body.
+func EffectiveDeadline(options ...Option) (time.Time, bool) {
+	req := &request{}
+	req.apply(options)
+
+	now := time.Now()
+	var deadline time.Time
+	earliest := func(t time.Time) {
+		if deadline.IsZero() || t.Before(deadline) {
+			deadline = t
+		}
+	}
+	if req.ctx != nil {
+		if t, ok := req.ctx.Deadline(); ok {
+			earliest(t)
+		}
+	}
+	if req.timeout > 0 {
+		earliest(now.Add(req.timeout))
+	}
+	if req.client != nil && req.client.Timeout > 0 {
+		earliest(now.Add(req.client.Timeout))
+	}
+	return deadline, !deadline.IsZero()
+}
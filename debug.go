@@ -0,0 +1,73 @@
+package paste
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"sync"
+)
+
+// debugBodyLimit is the maximum request body size written by Debug.
+const debugBodyLimit = 64 << 10
+
+// Debug writes dumps of the HTTP requests and responses to w,
+// such as os.Stderr, to diagnose API issues. The Authorization,
+// Paste-Password and Paste-Delete-Token headers are redacted. Request bodies are written
+// if they can be read again, are at most 64 KiB and have no Password,
+// response bodies are not written.
+func Debug(w io.Writer) Option {
+	var mu sync.Mutex // Serializes the dumps of concurrent requests.
+	dump := func(b []byte, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			io.WriteString(w, "paste debug: "+err.Error()+"\n")
+			return
+		}
+		w.Write(b)
+		if !bytes.HasSuffix(b, []byte("\n")) {
+			io.WriteString(w, "\n")
+		}
+	}
+	onReq := func(req *request) func(*http.Request) {
+		return func(hr *http.Request) {
+			dr := new(http.Request)
+			*dr = *hr
+			dr.Header = redactHeader(hr.Header, "Authorization", "Paste-Password", "Paste-Delete-Token")
+			b, err := httputil.DumpRequestOut(dr, false)
+			// The body of an upload with a Password has the password.
+			if err == nil && hr.GetBody != nil && hr.ContentLength > 0 && hr.ContentLength <= debugBodyLimit &&
+				req.password == "" {
+				if body, err := hr.GetBody(); err == nil {
+					data, _ := ioutil.ReadAll(io.LimitReader(body, debugBodyLimit))
+					body.Close()
+					b = append(b, data...)
+				}
+			}
+			dump(b, err)
+		}
+	}
+	onResp := OnResponse(func(resp *http.Response) {
+		dr := new(http.Response)
+		*dr = *resp
+		dr.Header = redactHeader(resp.Header, "Set-Cookie")
+		dump(httputil.DumpResponse(dr, false))
+	})
+	return func(req *request) {
+		OnRequest(onReq(req))(req)
+		onResp(req)
+	}
+}
+
+// redactHeader returns a copy of h with the values of the keys redacted.
+func redactHeader(h http.Header, keys ...string) http.Header {
+	h = h.Clone()
+	for _, key := range keys {
+		if h.Get(key) != "" {
+			h.Set(key, "[redacted]")
+		}
+	}
+	return h
+}
@@ -0,0 +1,58 @@
+package paste
+
+import (
+	"errors"
+	"io/ioutil"
+)
+
+func deletePaste(paste string, req *request, options ...Option) error {
+	req.apply(options)
+
+	if req.tok == "" && req.deleteToken == "" {
+		return errors.New("deleting a paste requires a token or a delete token")
+	}
+	id, err := pasteID(paste)
+	if err != nil {
+		return err
+	}
+	hr, err := req.newRequest("DELETE", req.endpoint(id), nil)
+	if err != nil {
+		return err
+	}
+	if req.deleteToken != "" {
+		hr.Header.Set("Paste-Delete-Token", req.deleteToken)
+	}
+
+	resp, err := req.do(hr)
+	if err != nil {
+		return err
+	}
+	result, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return transportError(hr, err)
+	}
+	switch {
+	case resp.StatusCode == 200 || resp.StatusCode == 204:
+		return nil
+	case (resp.StatusCode == 404 || resp.StatusCode == 410) && req.ignoreMissing:
+		return nil
+	}
+	return apiError(resp, result)
+}
+
+// DeleteToken is the delete token of a paste, from UploadInfo,
+// for Delete without the Token of the paste owner.
+func DeleteToken(set string) Option {
+	return func(req *request) {
+		req.deleteToken = set
+	}
+}
+
+// Delete a paste.
+// paste can be a full paste URL or just the paste ID.
+// Requires the Token of the paste owner, or the DeleteToken of the paste.
+// Use IgnoreMissing to not fail if the paste was already deleted.
+func Delete(paste string, options ...Option) error {
+	return deletePaste(paste, &request{}, options...)
+}
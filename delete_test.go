@@ -0,0 +1,40 @@
+package paste_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"paste.run"
+	"paste.run/pastetest"
+)
+
+func TestDeleteToken(t *testing.T) {
+	srv := pastetest.NewServer()
+	defer srv.Close()
+	res, err := srv.Client.UploadInfo(strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.DeleteToken == "" {
+		t.Fatal("no delete token")
+	}
+	options := []paste.Option{paste.NoConfig(), paste.NoEnv(), paste.BaseURL(srv.URL)}
+
+	err = paste.NewClient(options...).Delete(res.URL)
+	if err == nil {
+		t.Fatal("Delete without a token succeeded")
+	}
+	err = paste.NewClient(options...).Delete(res.URL, paste.DeleteToken("wrong"))
+	var apiErr *paste.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 403 {
+		t.Fatalf("Delete with a wrong delete token: got %v, want a 403 APIError", err)
+	}
+	err = paste.NewClient(options...).Delete(res.URL, paste.DeleteToken(res.DeleteToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if srv.Pastes() != 0 {
+		t.Errorf("%d pastes after Delete, want 0", srv.Pastes())
+	}
+}
@@ -0,0 +1,152 @@
+package paste
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// deviceClientID is the OAuth client ID of the package.
+const deviceClientID = "paste.run-go"
+
+// Errors of DeviceLogin.Wait.
+var (
+	ErrLoginDenied  = errors.New("login denied")
+	ErrLoginExpired = errors.New("login code expired")
+)
+
+// DeviceLogin is a pending device authorization, see StartDeviceLogin.
+type DeviceLogin struct {
+	UserCode                string    // Code for the user to enter at VerificationURL.
+	VerificationURL         string    // Page to approve the login.
+	VerificationURLComplete string    // Page with the code filled in, can be empty.
+	Expires                 time.Time // When the code expires.
+	Interval                time.Duration
+
+	deviceCode string
+	req        *request
+}
+
+func startDeviceLogin(req *request, options ...Option) (*DeviceLogin, error) {
+	req.apply(options)
+
+	var resp struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               int    `json:"expires_in"`
+		Interval                int    `json:"interval"`
+	}
+	err := req.postForm("oauth/device/code", url.Values{"client_id": {deviceClientID}}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.DeviceCode == "" || resp.UserCode == "" || resp.VerificationURI == "" {
+		return nil, errors.New("invalid device authorization response")
+	}
+	if resp.ExpiresIn <= 0 {
+		resp.ExpiresIn = 900
+	}
+	d := &DeviceLogin{
+		UserCode:                resp.UserCode,
+		VerificationURL:         resp.VerificationURI,
+		VerificationURLComplete: resp.VerificationURIComplete,
+		Expires:                 time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second),
+		Interval:                time.Duration(resp.Interval) * time.Second,
+		deviceCode:              resp.DeviceCode,
+		req:                     req,
+	}
+	if d.Interval <= 0 {
+		d.Interval = 5 * time.Second
+	}
+	return d, nil
+}
+
+// StartDeviceLogin starts an OAuth device authorization, to get a token
+// without copying it from the website. Show the UserCode and VerificationURL
+// to the user, then call Wait to get the token once the user approves.
+func StartDeviceLogin(options ...Option) (*DeviceLogin, error) {
+	return startDeviceLogin(&request{}, options...)
+}
+
+// Wait polls until the user approves the login and returns the new token.
+// It returns ErrLoginDenied or ErrLoginExpired if the login failed,
+// or the ctx error if ctx is done first.
+func (d *DeviceLogin) Wait(ctx context.Context) (string, error) {
+	form := url.Values{
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		"device_code": {d.deviceCode},
+		"client_id":   {deviceClientID},
+	}
+	req := *d.req
+	req.ctx = ctx
+	interval := d.Interval
+	for {
+		t := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return "", ctx.Err()
+		case <-t.C:
+		}
+
+		var resp struct {
+			AccessToken string `json:"access_token"`
+		}
+		err := req.postForm("oauth/token", form, &resp)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			var oauthErr struct {
+				Error string `json:"error"`
+			}
+			json.Unmarshal([]byte(apiErr.Message), &oauthErr)
+			switch oauthErr.Error {
+			case "authorization_pending":
+				continue
+			case "slow_down":
+				interval += 5 * time.Second
+				continue
+			case "access_denied":
+				return "", ErrLoginDenied
+			case "expired_token":
+				return "", ErrLoginExpired
+			}
+		}
+		if err != nil {
+			return "", err
+		}
+		if resp.AccessToken == "" {
+			return "", errors.New("invalid token response")
+		}
+		return resp.AccessToken, nil
+	}
+}
+
+// postForm posts the form to the endpoint at path and decodes the JSON response.
+func (req *request) postForm(path string, form url.Values, v interface{}) error {
+	hr, err := req.newRequest("POST", req.endpoint(path), strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	hr.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	hr.Header.Set("Accept", "application/json")
+
+	resp, err := req.do(hr)
+	if err != nil {
+		return err
+	}
+	result, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return transportError(hr, err)
+	}
+	if resp.StatusCode != 200 {
+		return apiError(resp, result)
+	}
+	return json.Unmarshal(result, v)
+}
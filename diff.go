@@ -0,0 +1,271 @@
+package paste
+
+import (
+	"bufio"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DiffLine is a line of a DiffHunk.
+type DiffLine struct {
+	Op   byte   // ' ' for unchanged, '-' for removed or '+' for added
+	Text string // Line without the newline
+}
+
+// DiffHunk is a hunk of a line-based unified diff.
+// Line numbers start at 1.
+type DiffHunk struct {
+	OldStart int // First line of the hunk in the old paste
+	OldLines int // Number of old lines in the hunk
+	NewStart int // First line of the hunk in the new paste
+	NewLines int // Number of new lines in the hunk
+	Lines    []DiffLine
+}
+
+// String returns the hunk in unified diff format.
+func (h DiffHunk) String() string {
+	var sb strings.Builder
+	sb.WriteString("@@ -")
+	sb.WriteString(diffRange(h.OldStart, h.OldLines))
+	sb.WriteString(" +")
+	sb.WriteString(diffRange(h.NewStart, h.NewLines))
+	sb.WriteString(" @@\n")
+	for _, line := range h.Lines {
+		sb.WriteByte(line.Op)
+		sb.WriteString(line.Text)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func diffRange(start, lines int) string {
+	if lines == 0 {
+		start-- // Empty ranges refer to the line before.
+	}
+	if lines == 1 {
+		return strconv.Itoa(start)
+	}
+	return strconv.Itoa(start) + "," + strconv.Itoa(lines)
+}
+
+// diffContext is the number of unchanged lines around changes in a hunk.
+const diffContext = 3
+
+// diffLines returns the shortest edit script from a to b, with the linear
+// space variant of Myers' algorithm, so large pastes can be compared.
+// Removed lines come before added lines in each change.
+func diffLines(a, b []string) []DiffLine {
+	n := len(a) + len(b) + 4
+	d := &differ{a: a, b: b, vf: make([]int, n), vb: make([]int, n)}
+	d.diff(0, len(a), 0, len(b))
+
+	// Sort each change, a run of removed and added lines.
+	lines := d.lines
+	for i := 0; i < len(lines); {
+		if lines[i].Op == ' ' {
+			i++
+			continue
+		}
+		j := i
+		for j < len(lines) && lines[j].Op != ' ' {
+			j++
+		}
+		sort.SliceStable(lines[i:j], func(x, y int) bool {
+			return lines[i+x].Op == '-' && lines[i+y].Op == '+'
+		})
+		i = j
+	}
+	return lines
+}
+
+// differ is the state of diffLines.
+type differ struct {
+	a, b   []string
+	vf, vb []int // Furthest reaching forward and reverse paths by diagonal.
+	lines  []DiffLine
+}
+
+// diff adds the edit script from a[a0:a1] to b[b0:b1] to the lines.
+func (d *differ) diff(a0, a1, b0, b1 int) {
+	for a0 < a1 && b0 < b1 && d.a[a0] == d.b[b0] {
+		d.lines = append(d.lines, DiffLine{' ', d.a[a0]})
+		a0++
+		b0++
+	}
+	suffix := a1
+	for a1 > a0 && b1 > b0 && d.a[a1-1] == d.b[b1-1] {
+		a1--
+		b1--
+	}
+
+	switch {
+	case a0 == a1:
+		for _, line := range d.b[b0:b1] {
+			d.lines = append(d.lines, DiffLine{'+', line})
+		}
+	case b0 == b1:
+		for _, line := range d.a[a0:a1] {
+			d.lines = append(d.lines, DiffLine{'-', line})
+		}
+	default:
+		x, y, u, v := d.middleSnake(a0, a1, b0, b1)
+		d.diff(a0, x, b0, y)
+		for _, line := range d.a[x:u] {
+			d.lines = append(d.lines, DiffLine{' ', line})
+		}
+		d.diff(u, a1, v, b1)
+	}
+
+	for _, line := range d.a[a1:suffix] {
+		d.lines = append(d.lines, DiffLine{' ', line})
+	}
+}
+
+// middleSnake returns the middle snake of a shortest edit script
+// from a[a0:a1] to b[b0:b1], from (x, y) to (u, v).
+// The sequences are not empty.
+func (d *differ) middleSnake(a0, a1, b0, b1 int) (x, y, u, v int) {
+	n, m := a1-a0, b1-b0
+	delta := n - m
+	odd := delta%2 != 0
+	max := (n + m + 1) / 2
+	off := max + 1
+	vf, vb := d.vf, d.vb
+	vf[off+1], vb[off+1] = 0, 0
+	for D := 0; D <= max; D++ {
+		// Forward paths, from the start.
+		for k := -D; k <= D; k += 2 {
+			var x int
+			if k == -D || (k != D && vf[off+k-1] < vf[off+k+1]) {
+				x = vf[off+k+1]
+			} else {
+				x = vf[off+k-1] + 1
+			}
+			y := x - k
+			sx, sy := x, y
+			for x < n && y < m && d.a[a0+x] == d.b[b0+y] {
+				x++
+				y++
+			}
+			vf[off+k] = x
+			if kr := delta - k; odd && kr >= -(D-1) && kr <= D-1 && x+vb[off+kr] >= n {
+				return a0 + sx, b0 + sy, a0 + x, b0 + y
+			}
+		}
+		// Reverse paths, from the end.
+		for kr := -D; kr <= D; kr += 2 {
+			var x int
+			if kr == -D || (kr != D && vb[off+kr-1] < vb[off+kr+1]) {
+				x = vb[off+kr+1]
+			} else {
+				x = vb[off+kr-1] + 1
+			}
+			y := x - kr
+			sx, sy := x, y
+			for x < n && y < m && d.a[a1-1-x] == d.b[b1-1-y] {
+				x++
+				y++
+			}
+			vb[off+kr] = x
+			if k := delta - kr; !odd && k >= -D && k <= D && x+vf[off+k] >= n {
+				return a1 - x, b1 - y, a1 - sx, b1 - sy
+			}
+		}
+	}
+	panic("paste: no middle snake")
+}
+
+// diffHunks groups the changed lines into hunks with context.
+func diffHunks(lines []DiffLine) []DiffHunk {
+	var hunks []DiffHunk
+	oldLine, newLine := 1, 1
+	for i := 0; i < len(lines); {
+		if lines[i].Op == ' ' {
+			oldLine++
+			newLine++
+			i++
+			continue
+		}
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		// Find the last change, joining changes close together.
+		end, unchanged := i, 0
+		for j := i; j < len(lines) && unchanged <= 2*diffContext; j++ {
+			if lines[j].Op == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+				end = j + 1
+			}
+		}
+		end += diffContext
+		if end > len(lines) {
+			end = len(lines)
+		}
+		h := DiffHunk{
+			OldStart: oldLine - (i - start),
+			NewStart: newLine - (i - start),
+			Lines:    lines[start:end],
+		}
+		for _, line := range h.Lines {
+			if line.Op != '+' {
+				h.OldLines++
+			}
+			if line.Op != '-' {
+				h.NewLines++
+			}
+		}
+		for ; i < end; i++ {
+			if lines[i].Op != '+' {
+				oldLine++
+			}
+			if lines[i].Op != '-' {
+				newLine++
+			}
+		}
+		hunks = append(hunks, h)
+	}
+	return hunks
+}
+
+// readLines reads the paste content lines, limited by MaxSize.
+func readLines(info PasteInfo, req *request) ([]string, error) {
+	defer info.Content.Close()
+	r, err := req.limitContent(info)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	return lines, sc.Err()
+}
+
+func diff(pasteA, pasteB string, req *request, options ...Option) ([]DiffHunk, error) {
+	var lines [2][]string
+	for i, paste := range [2]string{pasteA, pasteB} {
+		req := *req
+		info, err := get(paste, &req, options...)
+		if err != nil {
+			return nil, err
+		}
+		lines[i], err = readLines(info, &req)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return diffHunks(diffLines(lines[0], lines[1])), nil
+}
+
+// Diff gets two pastes and returns a line-based unified diff
+// of the changes from pasteA to pasteB.
+// Both pastes are read into memory, use MaxSize to limit their size.
+func Diff(pasteA, pasteB string, options ...Option) ([]DiffHunk, error) {
+	return diff(pasteA, pasteB, &request{}, options...)
+}
@@ -0,0 +1,132 @@
+package paste
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// lcs returns the length of the longest common subsequence of a and b.
+func lcs(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			switch {
+			case a[i] == b[j]:
+				cur[j+1] = prev[j] + 1
+			case prev[j+1] > cur[j]:
+				cur[j+1] = prev[j+1]
+			default:
+				cur[j+1] = cur[j]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// checkDiff checks that the lines edit a into b with the fewest changes.
+func checkDiff(t *testing.T, a, b []string, lines []DiffLine) {
+	t.Helper()
+	var old, new []string
+	changes := 0
+	for _, line := range lines {
+		if line.Op != '+' {
+			old = append(old, line.Text)
+		}
+		if line.Op != '-' {
+			new = append(new, line.Text)
+		}
+		if line.Op != ' ' {
+			changes++
+		}
+	}
+	if strings.Join(old, "\n") != strings.Join(a, "\n") || strings.Join(new, "\n") != strings.Join(b, "\n") {
+		t.Fatalf("diff of %q and %q doesn't edit one into the other: %v", a, b, lines)
+	}
+	if want := len(a) + len(b) - 2*lcs(a, b); changes != want {
+		t.Fatalf("diff of %q and %q has %d changes, want %d", a, b, changes, want)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct{ a, b string }{
+		{"", ""},
+		{"a", ""},
+		{"", "a"},
+		{"a b c", "a b c"},
+		{"a b c a b b a", "c b a b a c"},
+		{"a b c", "x y z"},
+		{"a x b x c", "a b c"},
+	}
+	for _, test := range tests {
+		a, b := strings.Fields(test.a), strings.Fields(test.b)
+		checkDiff(t, a, b, diffLines(a, b))
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	random := func() []string {
+		lines := make([]string, rnd.Intn(40))
+		for i := range lines {
+			lines[i] = string(rune('a' + rnd.Intn(4)))
+		}
+		return lines
+	}
+	for i := 0; i < 500; i++ {
+		a, b := random(), random()
+		checkDiff(t, a, b, diffLines(a, b))
+	}
+}
+
+func TestDiffLinesOrder(t *testing.T) {
+	a, b := []string{"a", "old1", "old2", "b"}, []string{"a", "new1", "new2", "b"}
+	got := diffLines(a, b)
+	want := "  a\n- old1\n- old2\n+ new1\n+ new2\n  b\n"
+	var sb strings.Builder
+	for _, line := range got {
+		sb.WriteString(string(line.Op) + " " + line.Text + "\n")
+	}
+	if sb.String() != want {
+		t.Errorf("diffLines =\n%s\nwant\n%s", sb.String(), want)
+	}
+}
+
+func TestDiffLinesLarge(t *testing.T) {
+	// The quadratic space algorithm needed gigabytes for this.
+	a := make([]string, 5000)
+	b := make([]string, 5000)
+	for i := range a {
+		a[i] = "a" + string(rune('0'+i%10))
+		b[i] = "b" + string(rune('0'+i%10))
+	}
+	b[0] = a[0]
+	lines := diffLines(a, b)
+	if len(lines) != 9999 {
+		t.Errorf("got %d lines, want 9999", len(lines))
+	}
+}
+
+func TestDiffHunks(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 20; i++ {
+		line := string(rune('a' + i))
+		a = append(a, line)
+		if i != 2 && i != 15 {
+			b = append(b, line)
+		}
+	}
+	b = append(b, "new")
+	hunks := diffHunks(diffLines(a, b))
+	var got []string
+	for _, h := range hunks {
+		got = append(got, h.String())
+	}
+	want := []string{
+		"@@ -1,6 +1,5 @@\n a\n b\n-c\n d\n e\n f\n",
+		"@@ -13,8 +12,8 @@\n m\n n\n o\n-p\n q\n r\n s\n t\n+new\n",
+	}
+	if strings.Join(got, "") != strings.Join(want, "") {
+		t.Errorf("hunks =\n%s\nwant\n%s", strings.Join(got, ""), strings.Join(want, ""))
+	}
+}
@@ -0,0 +1,146 @@
+package paste
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Include only the files matching any of the glob patterns for UploadDir.
+// Patterns match the slash-separated path relative to the directory,
+// or the base name, see path.Match.
+func Include(patterns ...string) Option {
+	return func(req *request) {
+		req.include = patterns
+	}
+}
+
+// Exclude the files and directories matching any of the glob patterns
+// for UploadDir, see Include.
+func Exclude(patterns ...string) Option {
+	return func(req *request) {
+		req.exclude = patterns
+	}
+}
+
+// matchAny reports if the relative path or its base name matches any pattern.
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// dirReader reads a directory as a gzip compressed tar archive.
+// The archive is written once reading starts, after the options are applied.
+type dirReader struct {
+	dir string
+	req *request
+	pr  *io.PipeReader
+}
+
+func (d *dirReader) Read(p []byte) (int, error) {
+	if d.pr == nil {
+		var pw *io.PipeWriter
+		d.pr, pw = io.Pipe()
+		go func() {
+			pw.CloseWithError(d.writeTar(pw))
+		}()
+	}
+	return d.pr.Read(p)
+}
+
+// Close stops writing the archive.
+func (d *dirReader) Close() error {
+	if d.pr != nil {
+		d.pr.Close()
+	}
+	return nil
+}
+
+func (d *dirReader) writeTar(w io.Writer) error {
+	zw := gzip.NewWriter(w)
+	tw := tar.NewWriter(zw)
+	err := filepath.Walk(d.dir, func(name string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(d.dir, name)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if matchAny(d.req.exclude, rel) {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !fi.Mode().IsRegular() {
+			return nil // Directories are implied, skip links and devices.
+		}
+		if len(d.req.include) != 0 && !matchAny(d.req.include, rel) {
+			return nil
+		}
+		hdr, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.CopyN(tw, f, hdr.Size)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	err = tw.Close()
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func uploadDir(dir string, req *request, options ...Option) (string, error) {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return "", err
+	}
+	if !fi.IsDir() {
+		return "", &os.PathError{Op: "upload", Path: dir, Err: os.ErrInvalid}
+	}
+	fn := filepath.Base(filepath.Clean(dir))
+	if req.title == "" {
+		req.title = fn
+	}
+	req.fileName = fn + ".tar.gz"
+	r := &dirReader{dir: dir, req: req}
+	defer r.Close()
+	return upload(r, req, options...)
+}
+
+// UploadDir uploads a directory as a gzip compressed tar paste,
+// the title defaults to the directory name.
+// Use Include and Exclude to select the files, see GetTar.
+func UploadDir(dir string, options ...Option) (string, error) {
+	return uploadDir(dir, &request{}, options...)
+}
@@ -0,0 +1,158 @@
+package paste
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// downloadName returns the file name for a paste downloaded to a directory,
+// the paste title if it is a usable file name, or else the paste ID.
+func downloadName(paste string, info PasteInfo) (string, error) {
+	name := info.Title
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		var err error
+		name, err = urlPasteID(strings.SplitN(paste, "#", 2)[0])
+		if err != nil {
+			return "", err
+		}
+	}
+	return name, nil
+}
+
+// writeFileAtomic writes r to a temporary file, then renames it to path.
+func writeFileAtomic(path string, r io.Reader, info PasteInfo) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	if err == nil && !info.Created.IsZero() {
+		err = os.Chtimes(f.Name(), info.Created, info.Created)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+func downloadFile(paste, path string, req *request, options ...Option) error {
+	info, err := get(paste, req, options...)
+	if err != nil {
+		return err
+	}
+	defer info.Content.Close()
+	r, err := req.limitContent(info)
+	if err != nil {
+		return err
+	}
+
+	if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+		name, err := downloadName(paste, info)
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(path, name)
+	}
+	if path == "" {
+		return errors.New("empty download path")
+	}
+	return writeFileAtomic(path, r, info)
+}
+
+// DownloadFile gets a paste and writes its content to the file at path,
+// replacing it only once the download completes.
+// If path is a directory, the file is named by the paste title or ID.
+// The file modification time is set to the paste creation time.
+// Use MaxSize to limit the size of the content.
+func DownloadFile(paste, path string, options ...Option) error {
+	return downloadFile(paste, path, &request{}, options...)
+}
+
+func resumeDownload(paste, path string, req *request, options ...Option) error {
+	part := path + ".part"
+	etagFile := part + ".etag"
+	fresh := *req
+	var etag string
+	var modTime time.Time
+	if fi, err := os.Stat(part); err == nil && fi.Mode().IsRegular() {
+		// The partial file has the ETag, or else the paste creation time,
+		// the server sends all the content if the paste changed since.
+		req.rangeStart = fi.Size()
+		modTime = fi.ModTime()
+		req.ifRange = modTime.UTC().Format(http.TimeFormat)
+		if b, err := ioutil.ReadFile(etagFile); err == nil {
+			etag = string(b)
+			req.ifRange = etag
+		}
+	}
+
+	info, err := get(paste, req, options...)
+	if isStatus(err, http.StatusRequestedRangeNotSatisfiable) {
+		req = &fresh // Start over.
+		info, err = get(paste, req, options...)
+	}
+	if err != nil {
+		return err
+	}
+	defer info.Content.Close()
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if req.rangeStart > 0 {
+		if etag != "" && info.ETag != etag ||
+			etag == "" && (info.Created.IsZero() || !info.Created.Equal(modTime)) {
+			return errors.New("partial download does not match the paste: " + part)
+		}
+		flags = os.O_WRONLY | os.O_APPEND
+	} else {
+		// Only a strong ETag can be used in If-Range.
+		os.Remove(etagFile)
+		if info.ETag != "" && !strings.HasPrefix(info.ETag, "W/") {
+			err = ioutil.WriteFile(etagFile, []byte(info.ETag), 0666)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	f, err := os.OpenFile(part, flags, 0666)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, info.Content)
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	if !info.Created.IsZero() {
+		// Also on error, to resume the download later.
+		if err2 := os.Chtimes(part, info.Created, info.Created); err == nil {
+			err = err2
+		}
+	}
+	if err != nil {
+		return err
+	}
+	os.Remove(etagFile)
+	return os.Rename(part, path)
+}
+
+// ResumeDownload is like DownloadFile, but keeps the content in the file
+// path+".part" until the download completes. If the file exists,
+// only the rest of the content is requested, and the file is checked
+// to be of the same paste by the ETag kept in path+".part.etag",
+// or without one by the paste creation time.
+// DecryptWithKey and StripBOM can't be used when resuming.
+func ResumeDownload(paste, path string, options ...Option) error {
+	return resumeDownload(paste, path, &request{}, options...)
+}
@@ -0,0 +1,72 @@
+package paste_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"paste.run"
+)
+
+func TestResumeDownloadIfRange(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	content, etag := "hello, world", `"v1"`
+	var ranges, ifRanges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		ifRanges = append(ifRanges, r.Header.Get("If-Range"))
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Created-At", created.Format(http.TimeFormat))
+		http.ServeContent(w, r, "", created, strings.NewReader(content))
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "paste")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "out")
+	download := func(partial string) string {
+		t.Helper()
+		err := ioutil.WriteFile(path+".part", []byte(partial), 0666)
+		if err == nil {
+			err = ioutil.WriteFile(path+".part.etag", []byte(`"v1"`), 0666)
+		}
+		if err == nil {
+			err = os.Chtimes(path+".part", created, created)
+		}
+		if err == nil {
+			err = paste.ResumeDownload("abc", path, paste.BaseURL(srv.URL), paste.HTTPClient(srv.Client()))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(path + ".part.etag"); !os.IsNotExist(err) {
+			t.Errorf("ETag file left after the download: %v", err)
+		}
+		return string(b)
+	}
+
+	if got := download("hello"); got != content {
+		t.Errorf("resumed download = %q, want %q", got, content)
+	}
+	if ranges[0] != "bytes=5-" || ifRanges[0] != `"v1"` {
+		t.Errorf("Range %q, If-Range %q; want %q, %q", ranges[0], ifRanges[0], "bytes=5-", `"v1"`)
+	}
+
+	// Changed with the same creation time, the stale bytes are not kept.
+	content, etag = "goodbye, world", `"v2"`
+	if got := download("hello"); got != content {
+		t.Errorf("download of a changed paste = %q, want %q", got, content)
+	}
+}
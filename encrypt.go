@@ -0,0 +1,59 @@
+package paste
+
+import (
+	"io"
+	"strings"
+
+	"paste.run/crypto"
+)
+
+// EncryptWithKey encrypts the content with key before upload,
+// the paste URL returned has the key in its fragment.
+// Use crypto.GenerateKey to create a key.
+func EncryptWithKey(key []byte) Option {
+	return func(req *request) {
+		req.encryptKey = key
+	}
+}
+
+// DecryptWithKey decrypts the content from Get with key.
+// Not needed when getting a paste URL with the key in its fragment.
+func DecryptWithKey(key []byte) Option {
+	return func(req *request) {
+		req.decryptKey = key
+	}
+}
+
+// splitKey returns the paste without the URL fragment,
+// and sets the decryption key from the fragment if not already set.
+func (req *request) splitKey(paste string) (string, error) {
+	i := strings.IndexByte(paste, '#')
+	if i == -1 {
+		return paste, nil
+	}
+	if req.decryptKey == nil {
+		key, err := crypto.DecodeKey(paste[i+1:])
+		if err != nil {
+			return "", err
+		}
+		req.decryptKey = key
+	}
+	return paste[:i], nil
+}
+
+// decrypt decrypts the content with the decryption key.
+func (req *request) decrypt(info PasteInfo) (PasteInfo, error) {
+	r, err := crypto.NewDecrypter(info.Content, req.decryptKey)
+	if err != nil {
+		info.Content.Close()
+		return PasteInfo{}, err
+	}
+	info.Content = readCloser{r, info.Content}
+	info.Size = -1
+	return info, nil
+}
+
+// encrypt returns r encrypted with the encryption key.
+func (req *request) encrypt(r io.Reader) (io.Reader, error) {
+	return crypto.NewEncrypter(r, req.encryptKey)
+}
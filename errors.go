@@ -0,0 +1,195 @@
+package paste
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// APIError is an error response from the server.
+type APIError struct {
+	StatusCode int
+	Message    string // Error message from the server
+	Headers    http.Header
+
+	// RateLimit is set for 429 Too Many Requests responses
+	// with rate limit headers.
+	RateLimit *RateLimitInfo
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode)
+	}
+	return e.Message
+}
+
+// RetryAfter returns how long to wait before retrying,
+// from the Retry-After header or the rate limit reset, or 0 if unknown.
+func (e *APIError) RetryAfter() time.Duration {
+	if ra, ok := retryAfter(e.Headers.Get("Retry-After")); ok {
+		return ra
+	}
+	if e.RateLimit != nil && !e.RateLimit.Reset.IsZero() {
+		return time.Until(e.RateLimit.Reset)
+	}
+	return 0
+}
+
+// Is reports whether the error is the sentinel error for its status code.
+func (e *APIError) Is(target error) bool {
+	switch e.StatusCode {
+	case http.StatusNotFound, http.StatusGone:
+		return target == ErrNotFound
+	case http.StatusUnauthorized:
+		return target == ErrUnauthorized
+	case http.StatusRequestEntityTooLarge:
+		return target == ErrTooLarge
+	case http.StatusTooManyRequests:
+		return target == ErrRateLimited
+	case http.StatusLocked:
+		return target == ErrLocked
+	}
+	return false
+}
+
+func apiError(resp *http.Response, body []byte) error {
+	err := &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    strings.TrimSpace(string(body)),
+		Headers:    resp.Header,
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if rl, ok := parseRateLimit(resp.Header); ok {
+			err.RateLimit = &rl
+		}
+	}
+	return err
+}
+
+// TransportError is an error sending a request or reading its response,
+// as opposed to an error reported by the server.
+// If the request Context was done, Err is the Context error.
+type TransportError struct {
+	Method string
+	URL    string
+	Err    error
+}
+
+func (e *TransportError) Error() string {
+	return e.Method + " " + e.URL + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+func transportError(hr *http.Request, err error) error {
+	if ctxErr := hr.Context().Err(); ctxErr != nil {
+		err = ctxErr
+	} else if uerr, ok := err.(*url.Error); ok {
+		err = uerr.Err
+	}
+	return &TransportError{hr.Method, hr.URL.String(), err}
+}
+
+// Errors for common failures, use errors.Is to check for them.
+// An APIError is one of them by its status code.
+var (
+	ErrNotFound     = errors.New("paste not found")         // 404 or 410
+	ErrUnauthorized = errors.New("unauthorized")            // 401
+	ErrTooLarge     = errors.New("paste too large")         // 413, or over MaxSize
+	ErrRateLimited  = errors.New("rate limited")            // 429
+	ErrLocked       = errors.New("paste is locked")         // 423, see SetLocked
+	ErrTooSlow      = errors.New("paste download too slow") // See MinThroughput
+	ErrChecksum     = errors.New("paste checksum mismatch") // See VerifyChecksum
+	ErrNotModified  = errors.New("paste not modified")      // 304, see IfNoneMatch
+)
+
+// FriendlyError returns a short message for err to show to users,
+// such as "request timed out" or "could not resolve host api.paste.run".
+// It returns err.Error() for errors it does not know.
+func FriendlyError(err error) string {
+	if err == nil {
+		return ""
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "request timed out"
+	case errors.Is(err, context.Canceled):
+		return "request canceled"
+	case errors.Is(err, ErrTooLarge):
+		return "paste too large"
+	case errors.Is(err, ErrLocked):
+		return "paste is locked"
+	case errors.Is(err, ErrTooSlow):
+		return "download too slow"
+	case errors.Is(err, ErrChecksum):
+		return "download corrupted"
+	}
+
+	var aerr *APIError
+	if errors.As(err, &aerr) {
+		return friendlyAPIError(aerr)
+	}
+
+	var terr *TransportError
+	if !errors.As(err, &terr) {
+		return err.Error()
+	}
+	var dnsErr *net.DNSError
+	var certErr x509.UnknownAuthorityError
+	var hostErr x509.HostnameError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &dnsErr):
+		return "could not resolve host " + dnsErr.Name
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return "connection refused"
+	case errors.Is(err, syscall.ENETUNREACH), errors.Is(err, syscall.EHOSTUNREACH):
+		return "network unreachable"
+	case errors.As(err, &certErr), errors.As(err, &hostErr):
+		return "invalid server certificate"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "network timed out"
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+		return "connection closed by server"
+	}
+	return "network error: " + terr.Err.Error()
+}
+
+func friendlyAPIError(err *APIError) string {
+	switch code := err.StatusCode; {
+	case code == http.StatusNotFound, code == http.StatusGone:
+		return "paste not found"
+	case code == http.StatusUnauthorized:
+		return "invalid or missing token"
+	case code == http.StatusForbidden:
+		return "permission denied"
+	case code == http.StatusRequestEntityTooLarge:
+		return "paste too large"
+	case code == http.StatusTooManyRequests:
+		if d := err.RetryAfter(); d > 0 {
+			return "rate limited, retry in " + d.Round(time.Second).String()
+		}
+		return "rate limited, retry later"
+	case code >= 500:
+		return "server error, try again later"
+	}
+	return err.Error()
+}
+
+// isStatus reports if err is an APIError with the status code.
+func isStatus(err error, code int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == code
+}
@@ -0,0 +1,63 @@
+package paste
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NamedReader is a file of a multi-file paste.
+type NamedReader struct {
+	Name   string
+	Reader io.Reader
+}
+
+func uploadFiles(files []NamedReader, req *request, options ...Option) (string, error) {
+	req.files = append([]NamedReader{}, files...)
+	return upload(nil, req, options...)
+}
+
+// UploadFiles uploads the files as one paste.
+func UploadFiles(files []NamedReader, options ...Option) (string, error) {
+	return uploadFiles(files, &request{}, options...)
+}
+
+func uploadFilePaths(paths []string, req *request, options ...Option) (string, error) {
+	files := make([]NamedReader, 0, len(paths))
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		files = append(files, NamedReader{filepath.Base(path), f})
+	}
+	return uploadFiles(files, req, options...)
+}
+
+// UploadFilePaths uploads files on the filesystem as one paste,
+// named by their base names. The paths are a slice rather than variadic
+// so that, like the other uploads, it takes options:
+//
+//	paste.UploadFilePaths([]string{"main.go", "go.mod"}, paste.Title("Example"))
+func UploadFilePaths(paths []string, options ...Option) (string, error) {
+	return uploadFilePaths(paths, &request{}, options...)
+}
+
+// parseFiles returns the file names of a multi-file paste,
+// they are escaped and separated by commas.
+func parseFiles(h http.Header) []string {
+	var result []string
+	for _, name := range strings.Split(h.Get("Paste-Files"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			if unescaped, err := url.PathUnescape(name); err == nil {
+				name = unescaped
+			}
+			result = append(result, name)
+		}
+	}
+	return result
+}
@@ -0,0 +1,86 @@
+package paste
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var errFlightPanic = errors.New("shared Get panicked")
+
+type flightCall struct {
+	wg   sync.WaitGroup
+	info PasteInfo
+	data []byte
+	err  error
+}
+
+// flightGroup shares the result of concurrent Gets of the same paste.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// do calls fn, or waits for the call in flight with the same key.
+func (g *flightGroup) do(key string, fn func() (PasteInfo, []byte, error)) (PasteInfo, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flightCall{}
+	}
+	c := g.calls[key]
+	if c == nil {
+		c = &flightCall{}
+		c.wg.Add(1)
+		g.calls[key] = c
+		g.mu.Unlock()
+
+		g.call(c, key, fn)
+	} else {
+		g.mu.Unlock()
+		c.wg.Wait()
+	}
+
+	if c.err != nil {
+		return PasteInfo{}, c.err
+	}
+	info := c.info
+	info.Content = ioutil.NopCloser(bytes.NewReader(c.data))
+	return info, nil
+}
+
+// call calls fn for c, and ends c also if fn panics.
+func (g *flightGroup) call(c *flightCall, key string, fn func() (PasteInfo, []byte, error)) {
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		c.wg.Done()
+	}()
+	c.err = errFlightPanic // Unless fn returns.
+	c.info, c.data, c.err = fn()
+}
+
+// flightKey returns the key of a Get of pasteURL, with every input
+// changing the response, so only identical Gets share a request.
+func (req *request) flightKey(pasteURL string) string {
+	key := []string{
+		pasteURL,
+		req.tok,
+		req.password,
+		req.params.Encode(),
+		strconv.FormatBool(req.rawEncoding),
+		strconv.FormatBool(req.verifyChecksum),
+		strconv.FormatInt(req.maxSize, 10),
+		req.userAgent,
+		strconv.Itoa(len(req.products)),
+	}
+	key = append(key, req.products...)
+	key = append(key, req.headers...)
+	for i, s := range key {
+		key[i] = strconv.Quote(s)
+	}
+	return strings.Join(key, " ")
+}
@@ -0,0 +1,46 @@
+package paste
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFlightPanic(t *testing.T) {
+	var g flightGroup
+	started, release := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer func() { recover() }()
+		g.do("key", func() (PasteInfo, []byte, error) {
+			close(started)
+			<-release
+			panic("hook")
+		})
+	}()
+	<-started
+
+	done := make(chan error)
+	go func() {
+		_, err := g.do("key", func() (PasteInfo, []byte, error) {
+			return PasteInfo{}, nil, nil
+		})
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond) // Wait for the call in flight.
+	close(release)
+	select {
+	case err := <-done:
+		if err != errFlightPanic && err != nil {
+			t.Errorf("got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("waiter blocked after the call panicked")
+	}
+
+	// The key is not left in flight.
+	_, err := g.do("key", func() (PasteInfo, []byte, error) {
+		return PasteInfo{}, []byte("ok"), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
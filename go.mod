@@ -1,3 +1,3 @@
 module paste.run
 
-go 1.12
+go 1.13
@@ -0,0 +1,68 @@
+package paste
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+)
+
+// HighlightToken is a syntax highlighting token of the paste content.
+type HighlightToken struct {
+	Start int    `json:"start"` // Byte offset of the token start
+	End   int    `json:"end"`   // Byte offset after the token end
+	Class string `json:"class"` // Class or scope name, such as keyword
+}
+
+func getHighlights(paste string, req *request, options ...Option) ([]HighlightToken, error) {
+	req.apply(options)
+
+	id, err := pasteID(paste)
+	if err != nil {
+		return nil, err
+	}
+	geturl := req.endpoint(id + "/tokens")
+	if req.typ != "" {
+		geturl += "?lang=" + url.QueryEscape(req.typ)
+	}
+	hr, err := req.newRequest("GET", geturl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	hr.Header.Set("Accept", "application/json")
+
+	resp, err := req.do(hr)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == 404 || resp.StatusCode == 501 {
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    "highlight tokens are not available",
+			Headers:    resp.Header,
+		}
+	}
+	if resp.StatusCode != 200 {
+		result, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, transportError(hr, err)
+		}
+		return nil, apiError(resp, result)
+	}
+
+	var x struct {
+		Tokens []HighlightToken `json:"tokens"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&x)
+	if err != nil {
+		return nil, err
+	}
+	return x.Tokens, nil
+}
+
+// GetHighlights gets the syntax highlighting tokens of a paste,
+// for the paste's detected language, or use Type to choose the language.
+func GetHighlights(paste string, options ...Option) ([]HighlightToken, error) {
+	return getHighlights(paste, &request{}, options...)
+}
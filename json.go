@@ -0,0 +1,81 @@
+package paste
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// maxSizeReader is like io.LimitReader but returns ErrTooLarge
+// instead of EOF when there is more than n bytes.
+type maxSizeReader struct {
+	r io.Reader
+	n int64
+}
+
+func (mr *maxSizeReader) Read(p []byte) (int, error) {
+	// Not len(p) > mr.n+1, which overflows for a MaxSize of math.MaxInt64.
+	if mr.n < int64(len(p)) {
+		p = p[:mr.n+1]
+	}
+	n, err := mr.r.Read(p)
+	if int64(n) > mr.n {
+		return int(mr.n), ErrTooLarge
+	}
+	mr.n -= int64(n)
+	return n, err
+}
+
+// limitContent applies MaxSize to the paste content.
+func (req *request) limitContent(info PasteInfo) (io.Reader, error) {
+	if req.maxSize <= 0 {
+		return info.Content, nil
+	}
+	if info.Size > req.maxSize {
+		return nil, ErrTooLarge
+	}
+	return &maxSizeReader{info.Content, req.maxSize}, nil
+}
+
+func getJSON(paste string, v interface{}, req *request, options ...Option) (PasteInfo, error) {
+	info, err := get(paste, req, options...)
+	if err != nil {
+		return PasteInfo{}, err
+	}
+	defer info.Content.Close()
+	r, err := req.limitContent(info)
+	if err != nil {
+		return PasteInfo{}, err
+	}
+	err = json.NewDecoder(r).Decode(v)
+	if err != nil {
+		return PasteInfo{}, fmt.Errorf("paste %s: %w", paste, err)
+	}
+	info.Content = nil
+	return info, nil
+}
+
+// GetJSON gets a paste and decodes its JSON content into v.
+// The returned PasteInfo has a nil Content.
+// Use MaxSize to limit the size of the content.
+func GetJSON(paste string, v interface{}, options ...Option) (PasteInfo, error) {
+	return getJSON(paste, v, &request{}, options...)
+}
+
+// UploadJSON uploads v encoded as JSON, with Type "json".
+// Returns the new paste URL.
+func UploadJSON(v interface{}, options ...Option) (string, error) {
+	return uploadJSON(v, &request{}, options...)
+}
+
+func uploadJSON(v interface{}, req *request, options ...Option) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	if req.typ == "" {
+		req.typ = "json"
+	}
+	return upload(bytes.NewReader(b), req, options...)
+}
@@ -0,0 +1,31 @@
+package paste
+
+import (
+	"io/ioutil"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestMaxSizeReader(t *testing.T) {
+	tests := []struct {
+		content string
+		max     int64
+		err     error
+	}{
+		{"", 0, nil},
+		{"hello", 5, nil},
+		{"hello", 4, ErrTooLarge},
+		{"hello", 0, ErrTooLarge},
+		{"hello", math.MaxInt64, nil},
+	}
+	for _, test := range tests {
+		got, err := ioutil.ReadAll(&maxSizeReader{strings.NewReader(test.content), test.max})
+		if err != test.err {
+			t.Errorf("%q with max %d: got error %v, want %v", test.content, test.max, err, test.err)
+		}
+		if err == nil && string(got) != test.content {
+			t.Errorf("%q with max %d: got %q", test.content, test.max, got)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package paste
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+func streamLanguages(fn func(LanguageInfo) error, req *request, options ...Option) error {
+	resp, err := openLanguages(req, options...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if key, _ := tok.(string); key != "results" {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := expectDelim(dec, '['); err != nil {
+			return err
+		}
+		for dec.More() {
+			var lang LanguageInfo
+			if err := dec.Decode(&lang); err != nil {
+				return err
+			}
+			if err := fn(lang); err != nil {
+				return err
+			}
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return errors.New("unexpected JSON in languages response")
+	}
+	return nil
+}
+
+// StreamLanguages is like GetLanguages but calls fn for each language
+// as it is decoded, without holding all of them in memory.
+// If fn returns an error, streaming stops and that error is returned.
+func StreamLanguages(fn func(LanguageInfo) error, options ...Option) error {
+	return streamLanguages(fn, &request{}, options...)
+}
@@ -0,0 +1,102 @@
+package paste
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// PasteSummary is the summary of a paste in a listing.
+type PasteSummary struct {
+	ID      string    `json:"id"`
+	Title   string    `json:"title"`
+	Size    int64     `json:"size"` // Size of the paste content in bytes
+	Created time.Time `json:"created"`
+	Expires time.Time `json:"expires"` // IsZero if no expiration
+	Tags    []string  `json:"tags,omitempty"`
+}
+
+// PasteList is a page of pastes from ListPastes.
+type PasteList struct {
+	Pastes []PasteSummary `json:"pastes"`
+	Next   string         `json:"next"` // Cursor for After to get the next page, empty on the last page
+}
+
+// Limit the number of pastes in a listing, the server has its own maximum.
+func Limit(n int) Option {
+	return func(req *request) {
+		req.limit = n
+	}
+}
+
+// After gets the listing page after the cursor, from PasteList Next.
+func After(cursor string) Option {
+	return func(req *request) {
+		req.after = cursor
+	}
+}
+
+// listQuery returns the query string for the listing options.
+func (req *request) listQuery(query url.Values) string {
+	if req.limit > 0 {
+		query.Set("limit", strconv.Itoa(req.limit))
+	}
+	if req.after != "" {
+		query.Set("after", req.after)
+	}
+	if req.byTag != "" {
+		query.Set("tag", req.byTag)
+	}
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
+}
+
+// getList gets a listing of pastes from the endpoint URL.
+func (req *request) getList(listURL string) (PasteList, error) {
+	hr, err := req.newRequest("GET", listURL, nil)
+	if err != nil {
+		return PasteList{}, err
+	}
+
+	hr.Header.Set("Accept", "application/json")
+
+	resp, err := req.do(hr)
+	if err != nil {
+		return PasteList{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		result, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return PasteList{}, transportError(hr, err)
+		}
+		return PasteList{}, apiError(resp, result)
+	}
+
+	var list PasteList
+	err = json.NewDecoder(resp.Body).Decode(&list)
+	if err != nil {
+		return PasteList{}, err
+	}
+	return list, nil
+}
+
+func listPastes(req *request, options ...Option) (PasteList, error) {
+	req.apply(options)
+
+	if req.tok == "" {
+		return PasteList{}, errors.New("listing pastes requires a token")
+	}
+	return req.getList(req.endpoint("pastes") + req.listQuery(url.Values{}))
+}
+
+// ListPastes lists the pastes of the Token account, newest first.
+// Use Limit and After to page through the pastes, and ByTag to filter.
+func ListPastes(options ...Option) (PasteList, error) {
+	return listPastes(&request{}, options...)
+}
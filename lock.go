@@ -0,0 +1,50 @@
+package paste
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+func setLocked(paste string, locked bool, req *request, options ...Option) error {
+	req.apply(options)
+
+	if req.tok == "" {
+		return errors.New("locking a paste requires a token")
+	}
+	id, err := pasteID(paste)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{"locked": {strconv.FormatBool(locked)}}
+	hr, err := req.newRequest("PATCH", req.endpoint(id), strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+
+	hr.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := req.do(hr)
+	if err != nil {
+		return err
+	}
+	result, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return transportError(hr, err)
+	}
+	if resp.StatusCode != 200 && resp.StatusCode != 204 {
+		return apiError(resp, result)
+	}
+	return nil
+}
+
+// SetLocked locks or unlocks a paste.
+// A locked paste is read-only, changing it fails with ErrLocked.
+// Requires the Token of the paste owner.
+func SetLocked(paste string, locked bool, options ...Option) error {
+	return setLocked(paste, locked, &request{}, options...)
+}
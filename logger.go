@@ -0,0 +1,40 @@
+//go:build go1.21
+// +build go1.21
+
+package paste
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// WithLogger logs each HTTP request at debug level: the method and URL,
+// the attempt number of retries, the status or error, the duration
+// to get the response and the request and response sizes if known.
+func WithLogger(logger *slog.Logger) Option {
+	return onAttempt(func(hr *http.Request, attempt int, resp *http.Response, err error, d time.Duration) {
+		ctx := hr.Context()
+		if !logger.Enabled(ctx, slog.LevelDebug) {
+			return
+		}
+		attrs := []slog.Attr{
+			slog.String("method", hr.Method),
+			slog.String("url", hr.URL.Redacted()),
+			slog.Int("attempt", attempt),
+			slog.Duration("duration", d),
+		}
+		if hr.ContentLength > 0 {
+			attrs = append(attrs, slog.Int64("request_bytes", hr.ContentLength))
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		} else {
+			attrs = append(attrs, slog.Int("status", resp.StatusCode))
+			if resp.ContentLength >= 0 {
+				attrs = append(attrs, slog.Int64("response_bytes", resp.ContentLength))
+			}
+		}
+		logger.LogAttrs(ctx, slog.LevelDebug, "paste request", attrs...)
+	})
+}
@@ -0,0 +1,165 @@
+package paste
+
+import (
+	"bytes"
+	"container/list"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+)
+
+// lruCache is an in-memory cache of Get and GetLanguages results.
+type lruCache struct {
+	maxEntries int
+	maxBytes   int64
+	ttl        time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // Of *lruEntry, most recently used first.
+	size    int64
+	flight  flightGroup // Shares the requests of concurrent misses.
+}
+
+type lruEntry struct {
+	key       string
+	info      PasteInfo
+	data      []byte
+	languages []LanguageInfo
+	expires   time.Time
+}
+
+func newLRUCache(maxEntries int, maxBytes int64, ttl time.Duration) *lruCache {
+	return &lruCache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		ttl:        ttl,
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+// get returns the entry for key, or nil if not cached or expired.
+func (c *lruCache) get(key string) *lruEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	e := el.Value.(*lruEntry)
+	if time.Now().After(e.expires) {
+		c.remove(el)
+		return nil
+	}
+	c.order.MoveToFront(el)
+	return e
+}
+
+func (c *lruCache) add(e *lruEntry) {
+	size := int64(len(e.data))
+	if c.maxBytes > 0 && size > c.maxBytes {
+		return
+	}
+	e.expires = time.Now().Add(c.ttl)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[e.key]; ok {
+		c.remove(el)
+	}
+	c.entries[e.key] = c.order.PushFront(e)
+	c.size += size
+	for c.order.Len() > 0 && ((c.maxEntries > 0 && c.order.Len() > c.maxEntries) ||
+		(c.maxBytes > 0 && c.size > c.maxBytes)) {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *lruCache) remove(el *list.Element) {
+	e := c.order.Remove(el).(*lruEntry)
+	delete(c.entries, e.key)
+	c.size -= int64(len(e.data))
+}
+
+// WithCache returns a Client with the options of c that caches
+// the results of Get and GetLanguages in memory for ttl,
+// keeping at most maxEntries results and maxBytes of paste content,
+// or unlimited if 0. Pastes larger than maxBytes are not cached,
+// and are read from the response. Calls with options are not cached,
+// since options can change the results.
+func (c *Client) WithCache(maxEntries int, maxBytes int64, ttl time.Duration) *Client {
+	c.mu.Lock()
+	rotated := c.rotated
+	c.mu.Unlock()
+	return &Client{
+		options: c.options,
+		rotated: rotated,
+		cache:   newLRUCache(maxEntries, maxBytes, ttl),
+	}
+}
+
+// errNotShared is the flight error of a paste too large to cache,
+// which each Get reads from its own response.
+var errNotShared = errors.New("paste too large to share")
+
+// cachedGet gets a paste from the cache or adds it,
+// concurrent misses of the same paste share one request
+// if the paste is small enough to cache.
+func (c *Client) cachedGet(paste string) (PasteInfo, error) {
+	key := "get " + paste
+	if e := c.cache.get(key); e != nil {
+		info := e.info
+		info.Content = ioutil.NopCloser(bytes.NewReader(e.data))
+		return info, nil
+	}
+	var large PasteInfo // Paste too large to cache, for the Get which requested it.
+	info, err := c.cache.flight.do(key, func() (PasteInfo, []byte, error) {
+		info, err := get(paste, c.request())
+		if err != nil {
+			return PasteInfo{}, nil, err
+		}
+		r := io.Reader(info.Content)
+		if c.cache.maxBytes > 0 {
+			r = io.LimitReader(r, c.cache.maxBytes+1)
+		}
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			info.Content.Close()
+			return PasteInfo{}, nil, err
+		}
+		if c.cache.maxBytes > 0 && int64(len(data)) > c.cache.maxBytes {
+			// Too large to cache, read the rest from the response.
+			info.Content = readCloser{io.MultiReader(bytes.NewReader(data), info.Content), info.Content}
+			large = info
+			return PasteInfo{}, nil, errNotShared
+		}
+		info.Content.Close()
+		info.Content = nil
+		c.cache.add(&lruEntry{key: key, info: info, data: data})
+		return info, data, nil
+	})
+	switch {
+	case large.Content != nil:
+		return large, nil
+	case err == errNotShared:
+		// Another Get found the paste too large to share.
+		return get(paste, c.request())
+	}
+	return info, err
+}
+
+// cachedLanguages gets the languages from the cache or adds them.
+func (c *Client) cachedLanguages() ([]LanguageInfo, error) {
+	const key = "languages"
+	if e := c.cache.get(key); e != nil {
+		return append([]LanguageInfo(nil), e.languages...), nil
+	}
+	languages, err := getLanguages(c.request())
+	if err != nil {
+		return nil, err
+	}
+	c.cache.add(&lruEntry{key: key, languages: languages})
+	return append([]LanguageInfo(nil), languages...), nil
+}
@@ -0,0 +1,57 @@
+package paste_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"paste.run"
+	"paste.run/pastetest"
+)
+
+func TestWithCacheLarge(t *testing.T) {
+	srv := pastetest.NewServer()
+	defer srv.Close()
+	small, err := srv.Client.UploadString("small")
+	if err != nil {
+		t.Fatal(err)
+	}
+	largeContent := strings.Repeat("large ", 100)
+	large, err := srv.Client.UploadString(largeContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gets int
+	client := paste.NewClient(paste.NoConfig(), paste.NoEnv(), paste.BaseURL(srv.URL),
+		paste.OnRequest(func(hr *http.Request) {
+			gets++
+		})).WithCache(0, 16, time.Minute)
+	for _, test := range []struct {
+		paste, content string
+		gets           int
+	}{
+		{small, "small", 1},
+		{small, "small", 1},
+		{large, largeContent, 2},
+		{large, largeContent, 3},
+	} {
+		info, err := client.Get(test.paste)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadAll(info.Content)
+		info.Content.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != test.content {
+			t.Errorf("Get(%s) = %q, want %q", test.paste, b, test.content)
+		}
+		if gets != test.gets {
+			t.Errorf("after Get(%s): %d requests, want %d", test.paste, gets, test.gets)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Package metrics collects the request metrics of the paste client
+// and exposes them in the Prometheus text format:
+//
+//	m := metrics.New()
+//	client := paste.NewClient(paste.WithMetrics(m))
+//	http.Handle("/metrics", m)
+//
+// The metrics are:
+//
+//	paste_client_requests_total{method,code}       counter, code is "error" for transport errors
+//	paste_client_errors_total{method}              counter of transport errors and 5xx responses
+//	paste_client_request_duration_seconds{method}  histogram of the time to get the response
+//	paste_client_sent_bytes_total                  counter of request body bytes
+//	paste_client_received_bytes_total              counter of response body bytes
+//
+// The module has no dependencies, so a Metrics is not a Prometheus
+// Collector registered with a Registerer: serve it on its own endpoint,
+// or write it with WriteTo. Programs using the Prometheus client library
+// can instead implement paste.MetricsObserver with their own collectors,
+// registered with their Registerer:
+//
+//	type observer struct {
+//		requests *prometheus.CounterVec // With method and code labels.
+//		duration *prometheus.HistogramVec
+//		bytes    *prometheus.CounterVec // With a direction label.
+//	}
+//
+//	func (o *observer) ObserveRequest(method string, status int, err error, d time.Duration) {
+//		code := strconv.Itoa(status)
+//		if err != nil {
+//			code = "error"
+//		}
+//		o.requests.WithLabelValues(method, code).Inc()
+//		o.duration.WithLabelValues(method).Observe(d.Seconds())
+//	}
+//
+//	func (o *observer) ObserveBytes(sent, received int64) {
+//		o.bytes.WithLabelValues("sent").Add(float64(sent))
+//		o.bytes.WithLabelValues("received").Add(float64(received))
+//	}
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Buckets are the upper bounds in seconds of the request duration histogram.
+var Buckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics collects the request metrics, it implements paste.MetricsObserver.
+// A Metrics is safe for concurrent use.
+type Metrics struct {
+	mu        sync.Mutex
+	requests  map[[2]string]uint64 // By method and code.
+	errors    map[string]uint64    // By method.
+	durations map[string]*histogram
+	sent      int64
+	received  int64
+}
+
+type histogram struct {
+	counts []uint64 // By bucket, not cumulative.
+	count  uint64
+	sum    float64
+}
+
+// New returns a Metrics with no requests.
+func New() *Metrics {
+	return &Metrics{
+		requests:  map[[2]string]uint64{},
+		errors:    map[string]uint64{},
+		durations: map[string]*histogram{},
+	}
+}
+
+// ObserveRequest records a request attempt.
+func (m *Metrics) ObserveRequest(method string, status int, err error, d time.Duration) {
+	code := strconv.Itoa(status)
+	if err != nil {
+		code = "error"
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[[2]string{method, code}]++
+	if err != nil || status >= 500 {
+		m.errors[method]++
+	}
+	h := m.durations[method]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(Buckets))}
+		m.durations[method] = h
+	}
+	secs := d.Seconds()
+	for i, le := range Buckets {
+		if secs <= le {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += secs
+}
+
+// ObserveBytes records the body bytes sent and received.
+func (m *Metrics) ObserveBytes(sent, received int64) {
+	m.mu.Lock()
+	m.sent += sent
+	m.received += received
+	m.mu.Unlock()
+}
+
+// WriteTo writes the metrics in the Prometheus text format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	m.mu.Lock()
+	m.write(bw)
+	m.mu.Unlock()
+	err := bw.Flush()
+	return cw.n, err
+}
+
+func (m *Metrics) write(w io.Writer) {
+	fmt.Fprintln(w, "# HELP paste_client_requests_total HTTP requests by method and status code.")
+	fmt.Fprintln(w, "# TYPE paste_client_requests_total counter")
+	keys := make([][2]string, 0, len(m.requests))
+	for key := range m.requests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	for _, key := range keys {
+		fmt.Fprintf(w, "paste_client_requests_total{method=%s,code=%s} %d\n", label(key[0]), label(key[1]), m.requests[key])
+	}
+
+	fmt.Fprintln(w, "# HELP paste_client_errors_total HTTP requests failed with a transport error or 5xx status.")
+	fmt.Fprintln(w, "# TYPE paste_client_errors_total counter")
+	for _, method := range sortedKeys(m.errors) {
+		fmt.Fprintf(w, "paste_client_errors_total{method=%s} %d\n", label(method), m.errors[method])
+	}
+
+	fmt.Fprintln(w, "# HELP paste_client_request_duration_seconds Time to get the HTTP response.")
+	fmt.Fprintln(w, "# TYPE paste_client_request_duration_seconds histogram")
+	methods := make([]string, 0, len(m.durations))
+	for method := range m.durations {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	for _, method := range methods {
+		h := m.durations[method]
+		l := label(method)
+		var cumulative uint64
+		for i, le := range Buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "paste_client_request_duration_seconds_bucket{method=%s,le=\"%s\"} %d\n",
+				l, strconv.FormatFloat(le, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(w, "paste_client_request_duration_seconds_bucket{method=%s,le=\"+Inf\"} %d\n", l, h.count)
+		fmt.Fprintf(w, "paste_client_request_duration_seconds_sum{method=%s} %s\n",
+			l, strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(w, "paste_client_request_duration_seconds_count{method=%s} %d\n", l, h.count)
+	}
+
+	fmt.Fprintln(w, "# HELP paste_client_sent_bytes_total Request body bytes sent.")
+	fmt.Fprintln(w, "# TYPE paste_client_sent_bytes_total counter")
+	fmt.Fprintf(w, "paste_client_sent_bytes_total %d\n", m.sent)
+	fmt.Fprintln(w, "# HELP paste_client_received_bytes_total Response body bytes received.")
+	fmt.Fprintln(w, "# TYPE paste_client_received_bytes_total counter")
+	fmt.Fprintf(w, "paste_client_received_bytes_total %d\n", m.received)
+}
+
+// labelEscaper escapes a label value like Prometheus, unlike %q
+// which also escapes non-ASCII and other characters.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// label returns the quoted label value.
+func label(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
+
+func sortedKeys(m map[string]uint64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files")
+
+func TestWriteToGolden(t *testing.T) {
+	m := New()
+	m.ObserveRequest("GET", 200, nil, 3*time.Millisecond)
+	m.ObserveRequest("GET", 200, nil, 200*time.Millisecond)
+	m.ObserveRequest("GET", 0, errors.New("reset"), 30*time.Second)
+	m.ObserveRequest("POST", 503, nil, time.Second)
+	// Only backslash, double quote and newline are escaped.
+	m.ObserveRequest("a\"b\\c\nd é", 200, nil, time.Millisecond)
+	m.ObserveBytes(100, 2000)
+	m.ObserveBytes(5, 0)
+
+	var buf bytes.Buffer
+	n, err := m.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo = %d, wrote %d bytes", n, buf.Len())
+	}
+	got := buf.Bytes()
+
+	path := filepath.Join("testdata", "metrics.golden")
+	if *updateGolden {
+		err := ioutil.WriteFile(path, got, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("metrics differ from %s:\n%s", path, got)
+	}
+}
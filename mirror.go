@@ -0,0 +1,32 @@
+package paste
+
+import (
+	"errors"
+	"time"
+)
+
+// Mirror gets a paste using srcOptions and uploads it using dstOptions,
+// such as to copy a paste to another BaseURL with another Token.
+// The content is streamed, and the title, author, language and
+// remaining time before expiration are kept, unless set by dstOptions.
+// Returns the new paste URL.
+func Mirror(srcPaste string, srcOptions []Option, dstOptions []Option) (string, error) {
+	info, err := Get(srcPaste, srcOptions...)
+	if err != nil {
+		return "", err
+	}
+	defer info.Content.Close()
+
+	req := &request{
+		title:  info.Title,
+		author: info.Author,
+		typ:    info.Language,
+	}
+	if !info.Expires.IsZero() {
+		if !info.Expires.After(time.Now()) {
+			return "", errors.New("paste expired")
+		}
+		req.expires = info.Expires
+	}
+	return upload(info.Content, req, dstOptions...)
+}
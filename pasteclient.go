@@ -0,0 +1,329 @@
+package paste
+
+import (
+	"archive/tar"
+	"io"
+	"sync"
+	"time"
+)
+
+// Client makes requests with default options,
+// the options passed to its methods override the defaults.
+// A Client is safe for concurrent use.
+type Client struct {
+	options []Option
+
+	mu        sync.Mutex
+	rateLimit RateLimitInfo
+	hasLimit  bool
+
+	cache *lruCache // See WithCache.
+
+	rotated string // Token from RotateToken.
+}
+
+// NewClient returns a Client with the default options,
+// such as Token, BaseURL and HTTPClient.
+// The options override the environment defaults, see NoEnv,
+// which override the config file defaults, see LoadConfig and NoConfig.
+// An invalid config is returned by the requests of the Client.
+func NewClient(options ...Option) *Client {
+	probe := &request{}
+	for _, opt := range options {
+		opt(probe)
+	}
+	var defaults []Option
+	if !probe.noConfig {
+		defaults = append(defaults, configDefaults(LoadConfig())...)
+	}
+	if !probe.noEnv {
+		defaults = append(defaults, configDefaults(envOptions())...)
+	}
+	return &Client{options: append(defaults, options...)}
+}
+
+// configDefaults returns the config options, or an option failing the requests.
+func configDefaults(options []Option, err error) []Option {
+	if err != nil {
+		return []Option{func(req *request) {
+			req.err = err
+		}}
+	}
+	return options
+}
+
+// NoConfig makes NewClient not use the config file, see LoadConfig.
+func NoConfig() Option {
+	return func(req *request) {
+		req.noConfig = true
+	}
+}
+
+// NoEnv makes NewClient not use the PASTE_TOKEN, PASTE_BASE_URL
+// and PASTE_AUTHOR environment variables.
+func NoEnv() Option {
+	return func(req *request) {
+		req.noEnv = true
+	}
+}
+
+// request returns a new request with the default options.
+func (c *Client) request() *request {
+	req := &request{}
+	for _, opt := range c.options {
+		opt(req)
+	}
+	c.mu.Lock()
+	if c.rotated != "" {
+		req.tok = c.rotated
+	}
+	c.mu.Unlock()
+	req.onRateLimit = append(req.onRateLimit[:len(req.onRateLimit):len(req.onRateLimit)], c.setRateLimit)
+	return req
+}
+
+func (c *Client) setRateLimit(rl RateLimitInfo) {
+	c.mu.Lock()
+	c.rateLimit = rl
+	c.hasLimit = true
+	c.mu.Unlock()
+}
+
+// LastRateLimit returns the rate limit state of the last response
+// with rate limit headers, or false if there was none yet.
+func (c *Client) LastRateLimit() (RateLimitInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rateLimit, c.hasLimit
+}
+
+// RetryBudget returns the retries currently available in the
+// client's RetryBudget, or false if it has none.
+func (c *Client) RetryBudget() (float64, bool) {
+	req := c.request()
+	if req.budget == nil {
+		return 0, false
+	}
+	return req.budget.available(), true
+}
+
+// Upload the paste in r, see Upload.
+func (c *Client) Upload(r io.Reader, options ...Option) (string, error) {
+	return upload(r, c.request(), options...)
+}
+
+// UploadInfo uploads r and returns more about the new paste, see UploadInfo.
+func (c *Client) UploadInfo(r io.Reader, options ...Option) (UploadResult, error) {
+	return uploadInfo(r, c.request(), options...)
+}
+
+// UploadBytes uploads the paste content in b, see UploadBytes.
+func (c *Client) UploadBytes(b []byte, options ...Option) (string, error) {
+	return uploadBytes(b, c.request(), options...)
+}
+
+// UploadString uploads the paste content in s, see UploadString.
+func (c *Client) UploadString(s string, options ...Option) (string, error) {
+	return uploadBytes([]byte(s), c.request(), options...)
+}
+
+// UploadFile uploads a file on the filesystem, see UploadFile.
+func (c *Client) UploadFile(path string, options ...Option) (string, error) {
+	return uploadFile(path, c.request(), options...)
+}
+
+// UploadFiles uploads the files as one paste, see UploadFiles.
+func (c *Client) UploadFiles(files []NamedReader, options ...Option) (string, error) {
+	return uploadFiles(files, c.request(), options...)
+}
+
+// UploadFilePaths uploads files on the filesystem as one paste, see UploadFilePaths.
+func (c *Client) UploadFilePaths(paths []string, options ...Option) (string, error) {
+	return uploadFilePaths(paths, c.request(), options...)
+}
+
+// UploadDir uploads a directory as an archive, see UploadDir.
+func (c *Client) UploadDir(dir string, options ...Option) (string, error) {
+	return uploadDir(dir, c.request(), options...)
+}
+
+// UploadJSON uploads v encoded as JSON, see UploadJSON.
+func (c *Client) UploadJSON(v interface{}, options ...Option) (string, error) {
+	return uploadJSON(v, c.request(), options...)
+}
+
+// UploadVerify uploads r and checks the new paste, see UploadVerify.
+func (c *Client) UploadVerify(r io.ReadSeeker, options ...Option) (string, error) {
+	return uploadVerify(r, c.request(), options...)
+}
+
+// NewUploadWriter starts uploading a paste, see NewUploadWriter.
+func (c *Client) NewUploadWriter(options ...Option) *UploadWriter {
+	return newUploadWriter(c.request(), options...)
+}
+
+// Update replaces the content of a paste, see Update.
+func (c *Client) Update(paste string, r io.Reader, options ...Option) error {
+	return update(paste, r, c.request(), options...)
+}
+
+// Delete a paste, see Delete.
+func (c *Client) Delete(paste string, options ...Option) error {
+	return deletePaste(paste, c.request(), options...)
+}
+
+// SetLocked locks or unlocks a paste, see SetLocked.
+func (c *Client) SetLocked(paste string, locked bool, options ...Option) error {
+	return setLocked(paste, locked, c.request(), options...)
+}
+
+// SignedURL creates a URL to a private paste, see SignedURL.
+func (c *Client) SignedURL(paste string, ttl time.Duration, options ...Option) (string, error) {
+	return signedURL(paste, ttl, c.request(), options...)
+}
+
+// Get a paste, see Get.
+func (c *Client) Get(paste string, options ...Option) (PasteInfo, error) {
+	if c.cache != nil && len(options) == 0 {
+		return c.cachedGet(paste)
+	}
+	return get(paste, c.request(), options...)
+}
+
+// GetJSON gets a paste and decodes its JSON content, see GetJSON.
+func (c *Client) GetJSON(paste string, v interface{}, options ...Option) (PasteInfo, error) {
+	return getJSON(paste, v, c.request(), options...)
+}
+
+// GetTar gets a tar paste, see GetTar.
+func (c *Client) GetTar(paste string, options ...Option) (*tar.Reader, PasteInfo, func() error, error) {
+	return getTar(paste, c.request(), options...)
+}
+
+// GetChunks gets a paste in chunks, see GetChunks.
+func (c *Client) GetChunks(paste string, chunkSize int, fn func(chunk []byte) error, options ...Option) (PasteInfo, error) {
+	return getChunks(paste, chunkSize, fn, c.request(), options...)
+}
+
+// GetBytes gets the content of a paste in memory, see GetBytes.
+func (c *Client) GetBytes(paste string, maxBytes int64, options ...Option) ([]byte, PasteInfo, error) {
+	return getBytes(paste, maxBytes, c.request(), options...)
+}
+
+// GetString gets the content of a paste as a string, see GetString.
+func (c *Client) GetString(paste string, maxBytes int64, options ...Option) (string, PasteInfo, error) {
+	b, info, err := getBytes(paste, maxBytes, c.request(), options...)
+	return string(b), info, err
+}
+
+// DownloadFile gets a paste and writes it to a file, see DownloadFile.
+func (c *Client) DownloadFile(paste, path string, options ...Option) error {
+	return downloadFile(paste, path, c.request(), options...)
+}
+
+// ResumeDownload gets a paste to a file, resuming a partial download, see ResumeDownload.
+func (c *Client) ResumeDownload(paste, path string, options ...Option) error {
+	return resumeDownload(paste, path, c.request(), options...)
+}
+
+// GetTimed gets a paste with DownloadStats, see GetTimed.
+func (c *Client) GetTimed(paste string, options ...Option) (io.ReadCloser, *DownloadStats, PasteInfo, error) {
+	return getTimed(paste, c.request(), options...)
+}
+
+// GetHighlights gets the syntax highlighting tokens of a paste, see GetHighlights.
+func (c *Client) GetHighlights(paste string, options ...Option) ([]HighlightToken, error) {
+	return getHighlights(paste, c.request(), options...)
+}
+
+// ListPastes lists the pastes of the account, see ListPastes.
+func (c *Client) ListPastes(options ...Option) (PasteList, error) {
+	return listPastes(c.request(), options...)
+}
+
+// Search finds pastes, see Search.
+func (c *Client) Search(query string, options ...Option) ([]PasteSummary, error) {
+	return search(query, c.request(), options...)
+}
+
+// Stat gets the paste information without the content, see Stat.
+func (c *Client) Stat(paste string, options ...Option) (PasteInfo, error) {
+	return stat(paste, c.request(), options...)
+}
+
+// Stats gets the size information of a paste, see Stats.
+func (c *Client) Stats(paste string, options ...Option) (PasteStats, error) {
+	return stats(paste, c.request(), options...)
+}
+
+// GetStats gets the view statistics of a paste, see GetStats.
+func (c *Client) GetStats(paste string, options ...Option) (ViewStats, error) {
+	return getStats(paste, c.request(), options...)
+}
+
+// InfoBatch gets the information of many pastes, see InfoBatch.
+func (c *Client) InfoBatch(ids []string, options ...Option) (map[string]PasteInfo, error) {
+	return infoBatch(append([]string(nil), ids...), c.request(), options...)
+}
+
+// Diff compares two pastes, see Diff.
+func (c *Client) Diff(pasteA, pasteB string, options ...Option) ([]DiffHunk, error) {
+	return diff(pasteA, pasteB, c.request(), options...)
+}
+
+// GetLanguages gets information on languages, see GetLanguages.
+func (c *Client) GetLanguages(options ...Option) ([]LanguageInfo, error) {
+	if c.cache != nil && len(options) == 0 {
+		return c.cachedLanguages()
+	}
+	return getLanguages(c.request(), options...)
+}
+
+// StreamLanguages calls fn for each language, see StreamLanguages.
+func (c *Client) StreamLanguages(fn func(LanguageInfo) error, options ...Option) error {
+	return streamLanguages(fn, c.request(), options...)
+}
+
+// StartDeviceLogin starts an OAuth device authorization, see StartDeviceLogin.
+func (c *Client) StartDeviceLogin(options ...Option) (*DeviceLogin, error) {
+	return startDeviceLogin(c.request(), options...)
+}
+
+// WhoAmI checks the token and returns its account, see WhoAmI.
+func (c *Client) WhoAmI(options ...Option) (Account, error) {
+	return whoAmI(c.request(), options...)
+}
+
+// GetQuota gets the usage and limits of the account, see GetQuota.
+func (c *Client) GetQuota(options ...Option) (Quota, error) {
+	return getQuota(c.request(), options...)
+}
+
+// CreateToken creates a new API token, see CreateToken.
+func (c *Client) CreateToken(scopes []string, expiry time.Duration, options ...Option) (APIToken, error) {
+	return createToken(scopes, expiry, c.request(), options...)
+}
+
+// RotateToken replaces the token with a new token, see RotateToken.
+// If the client token was rotated, the client uses the new token for its next requests.
+func (c *Client) RotateToken(options ...Option) (APIToken, error) {
+	req := c.request()
+	own := req.tok
+	token, err := rotateToken(req, options...)
+	if err == nil && req.tok == own {
+		c.mu.Lock()
+		c.rotated = token.Token
+		c.mu.Unlock()
+	}
+	return token, err
+}
+
+// RevokeToken revokes an API token, see RevokeToken.
+func (c *Client) RevokeToken(id string, options ...Option) error {
+	return revokeToken(id, c.request(), options...)
+}
+
+// RateLimitStatus gets the current rate limit state, see RateLimitStatus.
+func (c *Client) RateLimitStatus(options ...Option) (RateLimitInfo, error) {
+	return rateLimitStatus(c.request(), options...)
+}
@@ -0,0 +1,92 @@
+package pastetest
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"paste.run"
+)
+
+// Fake is an in-memory paste.PasteService, without a server.
+// The options are ignored, use a Server to test them.
+// The zero value is ready to use, and a Fake is safe for concurrent use.
+type Fake struct {
+	mu     sync.Mutex
+	pastes map[string]fakePaste
+	nextID int
+}
+
+type fakePaste struct {
+	content []byte
+	created time.Time
+}
+
+var _ paste.PasteService = (*Fake)(nil)
+
+// Upload stores the content of r and returns its paste URL.
+func (f *Fake) Upload(r io.Reader, options ...paste.Option) (string, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.pastes == nil {
+		f.pastes = map[string]fakePaste{}
+	}
+	f.nextID++
+	id := "fake" + strconv.Itoa(f.nextID)
+	f.pastes[id] = fakePaste{b, time.Now().UTC().Truncate(time.Second)}
+	return "https://www.paste.run/" + id, nil
+}
+
+// Get returns a paste uploaded with Upload, or paste.ErrNotFound.
+func (f *Fake) Get(pasteURL string, options ...paste.Option) (paste.PasteInfo, error) {
+	f.mu.Lock()
+	p, ok := f.pastes[fakeID(pasteURL)]
+	f.mu.Unlock()
+	if !ok {
+		return paste.PasteInfo{}, paste.ErrNotFound
+	}
+	return paste.PasteInfo{
+		Content: ioutil.NopCloser(bytes.NewReader(p.content)),
+		Size:    int64(len(p.content)),
+		Type:    "text/plain; charset=utf-8",
+		Created: p.created,
+		Charset: "utf-8",
+	}, nil
+}
+
+// Delete removes a paste, or returns paste.ErrNotFound.
+func (f *Fake) Delete(pasteURL string, options ...paste.Option) error {
+	id := fakeID(pasteURL)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.pastes[id]; !ok {
+		return paste.ErrNotFound
+	}
+	delete(f.pastes, id)
+	return nil
+}
+
+// GetLanguages returns the Languages.
+func (f *Fake) GetLanguages(options ...paste.Option) ([]paste.LanguageInfo, error) {
+	return append([]paste.LanguageInfo(nil), Languages...), nil
+}
+
+// Len returns the number of pastes.
+func (f *Fake) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.pastes)
+}
+
+// fakeID returns the paste ID of a paste URL or ID.
+func fakeID(pasteURL string) string {
+	return pasteURL[strings.LastIndexByte(pasteURL, '/')+1:]
+}
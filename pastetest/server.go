@@ -0,0 +1,296 @@
+// Package pastetest provides a fake paste.run server for tests,
+// and Fake, an in-memory paste.PasteService.
+//
+// The server implements the upload, get, delete and languages endpoints,
+// and stores the pastes in memory:
+//
+//	srv := pastetest.NewServer()
+//	defer srv.Close()
+//	pasteURL, err := srv.Client.Upload(strings.NewReader("hello"))
+//	...
+//	info, err := srv.Client.Get(pasteURL)
+//
+// The paste URLs are like the real ones, such as https://www.paste.run/test1,
+// and the Client gets them from the server by their ID.
+package pastetest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"paste.run"
+)
+
+// Token is the token of the Server Client.
+const Token = "pastetest-token"
+
+// Languages are the languages of the Server.
+var Languages = []paste.LanguageInfo{
+	{Name: "Go", Class: ".go", Mode: "go"},
+	{Name: "JSON", Class: ".json", Mode: "javascript"},
+	{Name: "Markdown", Class: ".md", Mode: "markdown"},
+	{Name: "Plain Text", Class: ".txt"},
+	{Name: "Python", Class: ".py", Mode: "python"},
+}
+
+// Server is a fake paste.run server.
+type Server struct {
+	URL string // Base URL of the server
+
+	// Client is a client of the server with the Token.
+	Client *paste.Client
+
+	srv    *httptest.Server
+	mu     sync.Mutex
+	pastes map[string]*Paste
+	nextID int
+}
+
+// Paste is a paste stored by a Server.
+type Paste struct {
+	ID          string
+	Title       string
+	Author      string
+	Description string
+	Type        string // Language
+	Tags        []string
+	Content     []byte
+	ContentType string
+	Created     time.Time
+	Expires     time.Time // IsZero if no expiration
+	Token       string    // Token of the uploader, empty if none
+	DeleteToken string    // Deletes the paste without the Token
+}
+
+// NewServer starts a Server, call Close when done.
+func NewServer() *Server {
+	s := &Server{pastes: map[string]*Paste{}}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL + "/"
+	s.Client = paste.NewClient(
+		paste.NoConfig(),
+		paste.NoEnv(),
+		paste.BaseURL(s.URL),
+		paste.HTTPClient(s.srv.Client()),
+		paste.Token(Token),
+	)
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Paste returns a copy of the paste with the ID or paste URL, or false.
+func (s *Server) Paste(id string) (Paste, bool) {
+	id = fakeID(id)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pastes[id]
+	if !ok {
+		return Paste{}, false
+	}
+	return *p, true
+}
+
+// Pastes returns the number of pastes in the server.
+func (s *Server) Pastes() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pastes)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	switch {
+	case path == "" && r.Method == "POST":
+		s.upload(w, r)
+	case path == "languages" && r.Method == "GET":
+		s.languages(w, r)
+	case path != "" && !strings.Contains(path, "/") && (r.Method == "GET" || r.Method == "HEAD"):
+		s.get(w, r, path)
+	case path != "" && !strings.Contains(path, "/") && r.Method == "DELETE":
+		s.delete(w, r, path)
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+func token(r *http.Request) string {
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+func (s *Server) upload(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.Body = zr
+	} else if enc := r.Header.Get("Content-Encoding"); enc != "" && enc != "identity" {
+		http.Error(w, "unsupported encoding", http.StatusUnsupportedMediaType)
+		return
+	}
+	mr, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	p := &Paste{Created: time.Now().UTC().Truncate(time.Second), Token: token(r)}
+	hasFile := false
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b, err := ioutil.ReadAll(part)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		value := string(b)
+		switch part.FormName() {
+		case "file":
+			p.Content = append(p.Content, b...)
+			if !hasFile {
+				p.ContentType = part.Header.Get("Content-Type")
+			}
+			hasFile = true
+		case "author":
+			p.Author = value
+		case "title":
+			p.Title = value
+		case "desc":
+			p.Description = value
+		case "type":
+			p.Type = value
+		case "tag":
+			p.Tags = append(p.Tags, value)
+		case "expires":
+			p.Expires, err = http.ParseTime(value)
+			if err != nil {
+				http.Error(w, "invalid expires", http.StatusBadRequest)
+				return
+			}
+		}
+	}
+	if !hasFile {
+		http.Error(w, "missing file", http.StatusBadRequest)
+		return
+	}
+	if p.ContentType == "" || p.ContentType == "application/octet-stream" {
+		p.ContentType = "text/plain; charset=utf-8"
+	}
+
+	s.mu.Lock()
+	s.nextID++
+	p.ID = "test" + strconv.Itoa(s.nextID)
+	p.DeleteToken = "delete-" + p.ID
+	s.pastes[p.ID] = p
+	s.mu.Unlock()
+
+	pasteURL := "https://www.paste.run/" + p.ID
+	w.WriteHeader(http.StatusCreated)
+	if r.Header.Get("Accept") == "application/json" {
+		json.NewEncoder(w).Encode(paste.UploadResult{
+			ID:          p.ID,
+			URL:         pasteURL,
+			RawURL:      s.URL + p.ID + "?raw",
+			DeleteToken: p.DeleteToken,
+			Expires:     p.Expires,
+			Language:    p.Type,
+		})
+		return
+	}
+	io.WriteString(w, pasteURL+"\n")
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request, id string) {
+	s.mu.Lock()
+	p, ok := s.pastes[id]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "paste not found", http.StatusNotFound)
+		return
+	}
+	if !p.Expires.IsZero() && time.Now().After(p.Expires) {
+		http.Error(w, "paste expired", http.StatusGone)
+		return
+	}
+
+	sum := sha256.Sum256(p.Content)
+	h := w.Header()
+	h.Set("Content-Type", p.ContentType)
+	h.Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	h.Set("Content-SHA256", hex.EncodeToString(sum[:]))
+	h.Set("Created-At", p.Created.Format(http.TimeFormat))
+	if !p.Expires.IsZero() {
+		h.Set("Expires", p.Expires.UTC().Format(http.TimeFormat))
+	}
+	if p.Author != "" {
+		h.Set("Created-By", p.Author)
+	}
+	if p.Title != "" {
+		h.Set("Paste-Title", p.Title)
+	}
+	if p.Type != "" {
+		h.Set("Paste-Language", p.Type)
+	}
+	if len(p.Tags) != 0 {
+		h.Set("Paste-Tags", strings.Join(p.Tags, ","))
+	}
+	http.ServeContent(w, r, "", p.Created, bytes.NewReader(p.Content))
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request, id string) {
+	tok, deleteTok := token(r), r.Header.Get("Paste-Delete-Token")
+	if tok == "" && deleteTok == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pastes[id]
+	switch {
+	case !ok:
+		http.Error(w, "paste not found", http.StatusNotFound)
+	case (tok == "" || p.Token != tok) && (deleteTok == "" || p.DeleteToken != deleteTok):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	default:
+		delete(s.pastes, id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) languages(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	results := []paste.LanguageInfo{}
+	for _, lang := range Languages {
+		if q == "" || strings.Contains(strings.ToLower(lang.Name), strings.ToLower(q)) {
+			results = append(results, lang)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Q       string               `json:"q,omitempty"`
+		Results []paste.LanguageInfo `json:"results"`
+	}{q, results})
+}
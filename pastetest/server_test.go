@@ -0,0 +1,106 @@
+package pastetest_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"paste.run"
+	"paste.run/pastetest"
+)
+
+// roundTrip uploads a paste to s, gets it, then deletes it.
+func roundTrip(t *testing.T, s paste.PasteService) {
+	t.Helper()
+	content := "hello\npastetest\n"
+	pasteURL, err := s.Upload(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(pasteURL, "https://www.paste.run/") {
+		t.Errorf("paste URL %q is not like the real ones", pasteURL)
+	}
+
+	info, err := s.Get(pasteURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(info.Content)
+	info.Content.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != content || info.Size != int64(len(content)) {
+		t.Errorf("Get = %q (size %d), want %q", b, info.Size, content)
+	}
+	if info.Created.IsZero() {
+		t.Error("Get has no creation time")
+	}
+
+	err = s.Delete(pasteURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.Get(pasteURL)
+	if !errors.Is(err, paste.ErrNotFound) {
+		t.Errorf("Get of a deleted paste: got %v, want ErrNotFound", err)
+	}
+	err = s.Delete(pasteURL)
+	if !errors.Is(err, paste.ErrNotFound) {
+		t.Errorf("Delete of a deleted paste: got %v, want ErrNotFound", err)
+	}
+
+	languages, err := s.GetLanguages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(languages, pastetest.Languages) {
+		t.Errorf("GetLanguages = %v, want %v", languages, pastetest.Languages)
+	}
+}
+
+func TestServer(t *testing.T) {
+	srv := pastetest.NewServer()
+	defer srv.Close()
+	roundTrip(t, srv.Client)
+	if srv.Pastes() != 0 {
+		t.Errorf("%d pastes after the round trip, want 0", srv.Pastes())
+	}
+}
+
+func TestServerPaste(t *testing.T) {
+	srv := pastetest.NewServer()
+	defer srv.Close()
+	pasteURL, err := srv.Client.UploadString("hello", paste.Title("Hello"), paste.Tags("a", "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, ok := srv.Paste(pasteURL)
+	if !ok {
+		t.Fatalf("Paste(%s) not found", pasteURL)
+	}
+	if string(p.Content) != "hello" || p.Title != "Hello" || !reflect.DeepEqual(p.Tags, []string{"a", "b"}) {
+		t.Errorf("Paste(%s) = %+v", pasteURL, p)
+	}
+	if p.Token != pastetest.Token {
+		t.Errorf("Paste token %q, want %q", p.Token, pastetest.Token)
+	}
+	info, err := srv.Client.Get(pasteURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info.Content.Close()
+	if info.Title != "Hello" {
+		t.Errorf("Get title %q, want %q", info.Title, "Hello")
+	}
+}
+
+func TestFake(t *testing.T) {
+	var f pastetest.Fake
+	roundTrip(t, &f)
+	if f.Len() != 0 {
+		t.Errorf("%d pastes after the round trip, want 0", f.Len())
+	}
+}
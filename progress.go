@@ -0,0 +1,69 @@
+package paste
+
+import (
+	"io"
+	"os"
+)
+
+// Progress calls fn as the paste content is uploaded or read from Get,
+// with the bytes so far and the total bytes, or -1 if unknown.
+func Progress(fn func(written, total int64)) Option {
+	return func(req *request) {
+		req.progress = fn
+	}
+}
+
+// progressReader calls fn with the bytes read so far.
+type progressReader struct {
+	r       io.Reader
+	written *int64 // Shared by the files of a multi-file upload.
+	total   int64
+	fn      func(written, total int64)
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	if n > 0 {
+		*pr.written += int64(n)
+		pr.fn(*pr.written, pr.total)
+	}
+	return n, err
+}
+
+// contentSize returns the bytes left to read in r, or -1 if unknown.
+func contentSize(r io.Reader) int64 {
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case *os.File:
+		fi, err := r.Stat()
+		if err != nil || !fi.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return fi.Size() - offset
+	}
+	return -1
+}
+
+// progressFiles wraps the files to call the Progress func.
+func (req *request) progressFiles(files []NamedReader) []NamedReader {
+	total := int64(0)
+	for _, f := range files {
+		size := contentSize(f.Reader)
+		if size < 0 {
+			total = -1
+			break
+		}
+		total += size
+	}
+	written := new(int64)
+	result := make([]NamedReader, len(files))
+	for i, f := range files {
+		result[i] = NamedReader{f.Name, &progressReader{f.Reader, written, total, req.progress}}
+	}
+	return result
+}
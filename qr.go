@@ -0,0 +1,493 @@
+package paste
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+)
+
+// qrBlocks is the error correction block structure of a QR code version,
+// at error correction level M.
+type qrBlocks struct {
+	ec     int    // Error correction codewords per block.
+	blocks [2]int // Number of blocks in the two groups.
+	data   int    // Data codewords per block in the first group, one more in the second.
+}
+
+// qrVersions are the QR code versions 1 to 10, enough for URLs.
+var qrVersions = []qrBlocks{
+	{10, [2]int{1, 0}, 16},
+	{16, [2]int{1, 0}, 28},
+	{26, [2]int{1, 0}, 44},
+	{18, [2]int{2, 0}, 32},
+	{24, [2]int{2, 0}, 43},
+	{16, [2]int{4, 0}, 27},
+	{18, [2]int{4, 0}, 31},
+	{22, [2]int{2, 2}, 38},
+	{22, [2]int{3, 2}, 36},
+	{26, [2]int{4, 1}, 43},
+}
+
+// qrAlignment are the alignment pattern positions by version.
+var qrAlignment = [][]int{
+	nil,
+	{6, 18},
+	{6, 22},
+	{6, 26},
+	{6, 30},
+	{6, 34},
+	{6, 22, 38},
+	{6, 24, 42},
+	{6, 26, 46},
+	{6, 28, 50},
+}
+
+// qrCode is a QR code being drawn.
+type qrCode struct {
+	size     int
+	dark     [][]bool
+	function [][]bool // Modules not for data.
+}
+
+// qrEncode encodes data in byte mode as a QR code at error correction
+// level M, and returns its modules, true if dark.
+func qrEncode(data []byte) ([][]bool, error) {
+	version := 0
+	for v, blocks := range qrVersions {
+		capacity := blocks.blocks[0]*blocks.data + blocks.blocks[1]*(blocks.data+1)
+		countBits := 8
+		if v+1 >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+8*len(data) <= 8*capacity {
+			version = v + 1
+			break
+		}
+	}
+	if version == 0 {
+		return nil, errors.New("too long for a QR code")
+	}
+	blocks := qrVersions[version-1]
+	capacity := blocks.blocks[0]*blocks.data + blocks.blocks[1]*(blocks.data+1)
+
+	// Mode, length, data, terminator and padding.
+	var bits qrBits
+	bits.append(0x4, 4)
+	if version >= 10 {
+		bits.append(len(data), 16)
+	} else {
+		bits.append(len(data), 8)
+	}
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	for i := 0; i < 4 && bits.n < 8*capacity; i++ {
+		bits.append(0, 1)
+	}
+	for bits.n%8 != 0 {
+		bits.append(0, 1)
+	}
+	for pad := 0xec; len(bits.b) < capacity; pad ^= 0xec ^ 0x11 {
+		bits.b = append(bits.b, byte(pad))
+	}
+
+	// Split into blocks, add error correction and interleave.
+	divisor := rsDivisor(blocks.ec)
+	var dataBlocks, ecBlocks [][]byte
+	offset := 0
+	for group := 0; group < 2; group++ {
+		for i := 0; i < blocks.blocks[group]; i++ {
+			block := bits.b[offset : offset+blocks.data+group]
+			offset += len(block)
+			dataBlocks = append(dataBlocks, block)
+			ecBlocks = append(ecBlocks, rsRemainder(block, divisor))
+		}
+	}
+	var codewords []byte
+	for i := 0; i <= blocks.data; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				codewords = append(codewords, block[i])
+			}
+		}
+	}
+	for i := 0; i < blocks.ec; i++ {
+		for _, block := range ecBlocks {
+			codewords = append(codewords, block[i])
+		}
+	}
+
+	qr := newQRCode(version)
+	qr.drawCodewords(codewords)
+	best, bestPenalty := -1, 0
+	for mask := 0; mask < 8; mask++ {
+		qr.applyMask(mask)
+		qr.drawFormat(mask)
+		if penalty := qr.penalty(); best == -1 || penalty < bestPenalty {
+			best, bestPenalty = mask, penalty
+		}
+		qr.applyMask(mask) // Undo.
+	}
+	qr.applyMask(best)
+	qr.drawFormat(best)
+	return qr.dark, nil
+}
+
+// qrBits is a big-endian bit buffer.
+type qrBits struct {
+	b []byte
+	n int // Bits.
+}
+
+func (qb *qrBits) append(v, bits int) {
+	for i := bits - 1; i >= 0; i-- {
+		if qb.n%8 == 0 {
+			qb.b = append(qb.b, 0)
+		}
+		if v>>uint(i)&1 != 0 {
+			qb.b[qb.n/8] |= 0x80 >> uint(qb.n%8)
+		}
+		qb.n++
+	}
+}
+
+// gfMul multiplies in GF(2^8) with the QR code polynomial.
+func gfMul(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11d
+		z ^= int(y>>uint(i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+// rsDivisor returns the Reed-Solomon generator polynomial of the degree.
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMul(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMul(root, 2)
+	}
+	return result
+}
+
+// rsRemainder returns the Reed-Solomon error correction codewords of data.
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMul(d, factor)
+		}
+	}
+	return result
+}
+
+func newQRCode(version int) *qrCode {
+	size := version*4 + 17
+	qr := &qrCode{size: size}
+	qr.dark = make([][]bool, size)
+	qr.function = make([][]bool, size)
+	for y := range qr.dark {
+		qr.dark[y] = make([]bool, size)
+		qr.function[y] = make([]bool, size)
+	}
+
+	for i := 0; i < size; i++ {
+		qr.set(6, i, i%2 == 0)
+		qr.set(i, 6, i%2 == 0)
+	}
+	qr.drawFinder(3, 3)
+	qr.drawFinder(size-4, 3)
+	qr.drawFinder(3, size-4)
+	pos := qrAlignment[version-1]
+	for i, x := range pos {
+		for j, y := range pos {
+			last := len(pos) - 1
+			if !(i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0) {
+				qr.drawAlignment(x, y)
+			}
+		}
+	}
+	qr.drawFormat(0) // Reserve the modules.
+	if version >= 7 {
+		rem := version
+		for i := 0; i < 12; i++ {
+			rem = rem<<1 ^ (rem>>11)*0x1f25
+		}
+		bits := version<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := bits>>uint(i)&1 != 0
+			a, b := size-11+i%3, i/3
+			qr.set(a, b, dark)
+			qr.set(b, a, dark)
+		}
+	}
+	return qr
+}
+
+// set sets a function module.
+func (qr *qrCode) set(x, y int, dark bool) {
+	qr.dark[y][x] = dark
+	qr.function[y][x] = true
+}
+
+func (qr *qrCode) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx >= 0 && xx < qr.size && yy >= 0 && yy < qr.size {
+				dist := maxInt(absInt(dx), absInt(dy))
+				qr.set(xx, yy, dist != 2 && dist != 4)
+			}
+		}
+	}
+}
+
+func (qr *qrCode) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			qr.set(x+dx, y+dy, maxInt(absInt(dx), absInt(dy)) != 1)
+		}
+	}
+}
+
+// drawFormat draws the format bits for level M and the mask.
+func (qr *qrCode) drawFormat(mask int) {
+	data := 0<<3 | mask // Level M is 0.
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool {
+		return bits>>uint(i)&1 != 0
+	}
+
+	for i := 0; i <= 5; i++ {
+		qr.set(8, i, bit(i))
+	}
+	qr.set(8, 7, bit(6))
+	qr.set(8, 8, bit(7))
+	qr.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		qr.set(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		qr.set(qr.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		qr.set(8, qr.size-15+i, bit(i))
+	}
+	qr.set(8, qr.size-8, true)
+}
+
+// drawCodewords draws the data in the zigzag order.
+func (qr *qrCode) drawCodewords(data []byte) {
+	i := 0
+	for right := qr.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < qr.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = qr.size - 1 - vert
+				}
+				if !qr.function[y][x] && i < len(data)*8 {
+					qr.dark[y][x] = data[i/8]>>uint(7-i%8)&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask XORs the data modules with the mask pattern.
+func (qr *qrCode) applyMask(mask int) {
+	for y := 0; y < qr.size; y++ {
+		for x := 0; x < qr.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !qr.function[y][x] {
+				qr.dark[y][x] = !qr.dark[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores how hard the code is to scan, lower is better.
+func (qr *qrCode) penalty() int {
+	at := func(x, y int, vertical bool) bool {
+		if vertical {
+			return qr.dark[x][y]
+		}
+		return qr.dark[y][x]
+	}
+	penalty := 0
+	finder := []bool{true, false, true, true, true, false, true}
+	for _, vertical := range []bool{false, true} {
+		for y := 0; y < qr.size; y++ {
+			run := 0
+			for x := 0; x < qr.size; x++ {
+				if x > 0 && at(x, y, vertical) == at(x-1, y, vertical) {
+					run++
+				} else {
+					run = 1
+				}
+				if run == 5 {
+					penalty += 3
+				} else if run > 5 {
+					penalty++
+				}
+				// Finder-like pattern with 4 light modules on a side.
+				if x+7 <= qr.size {
+					match := true
+					for i, dark := range finder {
+						if at(x+i, y, vertical) != dark {
+							match = false
+							break
+						}
+					}
+					if match && (qr.light(x-4, x, y, vertical, at) || qr.light(x+7, x+11, y, vertical, at)) {
+						penalty += 40
+					}
+				}
+			}
+		}
+	}
+	dark := 0
+	for y := 0; y < qr.size; y++ {
+		for x := 0; x < qr.size; x++ {
+			if qr.dark[y][x] {
+				dark++
+			}
+			if x+1 < qr.size && y+1 < qr.size {
+				c := qr.dark[y][x]
+				if qr.dark[y][x+1] == c && qr.dark[y+1][x] == c && qr.dark[y+1][x+1] == c {
+					penalty += 3
+				}
+			}
+		}
+	}
+	total := qr.size * qr.size
+	penalty += absInt(dark*20-total*10) / total * 10
+	return penalty
+}
+
+// light reports if the modules from x0 to x1 are light, or outside the code.
+func (qr *qrCode) light(x0, x1, y int, vertical bool, at func(x, y int, vertical bool) bool) bool {
+	for x := x0; x < x1; x++ {
+		if x >= 0 && x < qr.size && at(x, y, vertical) {
+			return false
+		}
+	}
+	return true
+}
+
+func absInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func maxInt(x, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
+
+// qrQuietZone is the light border around a QR code, in modules.
+const qrQuietZone = 4
+
+// QRCode returns the paste URL as a QR code PNG image.
+func QRCode(pasteURL string) ([]byte, error) {
+	modules, err := qrEncode([]byte(pasteURL))
+	if err != nil {
+		return nil, err
+	}
+	const scale = 8
+	size := (len(modules) + 2*qrQuietZone) * scale
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			mx, my := x/scale-qrQuietZone, y/scale-qrQuietZone
+			c := color.Gray{255}
+			if mx >= 0 && mx < len(modules) && my >= 0 && my < len(modules) && modules[my][mx] {
+				c = color.Gray{0}
+			}
+			img.SetGray(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	err = png.Encode(&buf, img)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// QRCodeText returns the paste URL as a QR code for a terminal,
+// drawn with half block characters in black on white.
+func QRCodeText(pasteURL string) (string, error) {
+	modules, err := qrEncode([]byte(pasteURL))
+	if err != nil {
+		return "", err
+	}
+	dark := func(x, y int) bool {
+		x, y = x-qrQuietZone, y-qrQuietZone
+		return x >= 0 && x < len(modules) && y >= 0 && y < len(modules) && modules[y][x]
+	}
+	size := len(modules) + 2*qrQuietZone
+	var sb strings.Builder
+	for y := 0; y < size; y += 2 {
+		sb.WriteString("\x1b[30;47m")
+		for x := 0; x < size; x++ {
+			switch top, bottom := dark(x, y), dark(x, y+1); {
+			case top && bottom:
+				sb.WriteString("█")
+			case top:
+				sb.WriteString("▀")
+			case bottom:
+				sb.WriteString("▄")
+			default:
+				sb.WriteString(" ")
+			}
+		}
+		sb.WriteString("\x1b[0m\n")
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,60 @@
+package paste
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimitInfo is the rate limit state reported by the server.
+type RateLimitInfo struct {
+	Limit     int       `json:"limit"`     // Requests allowed per period
+	Remaining int       `json:"remaining"` // Requests remaining in the period
+	Reset     time.Time `json:"reset"`     // When the period resets
+}
+
+// parseRateLimit parses the X-RateLimit-* headers,
+// returns false if there are none.
+func parseRateLimit(h http.Header) (RateLimitInfo, bool) {
+	limit := h.Get("X-RateLimit-Limit")
+	if limit == "" {
+		return RateLimitInfo{}, false
+	}
+	var rl RateLimitInfo
+	rl.Limit, _ = strconv.Atoi(limit)
+	rl.Remaining, _ = strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rl.Reset = time.Unix(reset, 0)
+	}
+	return rl, true
+}
+
+func rateLimitStatus(req *request, options ...Option) (RateLimitInfo, error) {
+	req.apply(options)
+
+	hr, err := req.newRequest("HEAD", req.endpoint(""), nil)
+	if err != nil {
+		return RateLimitInfo{}, err
+	}
+
+	resp, err := req.do(hr)
+	if err != nil {
+		return RateLimitInfo{}, err
+	}
+	resp.Body.Close()
+	rl, ok := parseRateLimit(resp.Header)
+	if !ok {
+		if resp.StatusCode >= 400 {
+			return RateLimitInfo{}, apiError(resp, nil)
+		}
+		return RateLimitInfo{}, errors.New("no rate limit information")
+	}
+	return rl, nil
+}
+
+// RateLimitStatus gets the current rate limit state,
+// using a HEAD request on the base URL.
+func RateLimitStatus(options ...Option) (RateLimitInfo, error) {
+	return rateLimitStatus(&request{}, options...)
+}
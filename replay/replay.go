@@ -0,0 +1,190 @@
+// Package replay records HTTP interactions to a cassette file
+// and replays them, so tests of code using the paste client
+// don't need network access or a token:
+//
+//	tr, err := replay.New("testdata/upload.json", replay.Auto)
+//	...
+//	defer tr.Close()
+//	client := paste.NewClient(paste.HTTPClient(&http.Client{Transport: tr}))
+//
+// Run the tests once with a token to record the cassette,
+// then they replay it without one.
+// Requests are matched by method and URL, in the recorded order.
+// The request headers and bodies are not recorded.
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// Mode is whether a Transport records or replays.
+type Mode int
+
+// Modes of a Transport.
+const (
+	Replay Mode = iota // Replay the cassette, fail for requests not recorded.
+	Record             // Send the requests and record them, replacing the cassette.
+	Auto               // Replay the cassette if it exists, or else Record.
+)
+
+// Transport is a http.RoundTripper recording or replaying a cassette.
+type Transport struct {
+	// Transport sends the requests when recording,
+	// http.DefaultTransport is used if nil.
+	Transport http.RoundTripper
+
+	path string
+	mode Mode
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool // Replayed interactions.
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+type cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// New returns a Transport for the cassette file at path.
+// In Replay mode, the cassette is read and must exist.
+func New(path string, mode Mode) (*Transport, error) {
+	t := &Transport{path: path, mode: mode}
+	if mode == Record {
+		return t, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && mode == Auto {
+		t.mode = Record
+		return t, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var c cassette
+	err = json.Unmarshal(data, &c)
+	if err != nil {
+		return nil, errors.New("replay: invalid cassette " + path + ": " + err.Error())
+	}
+	t.mode = Replay
+	t.interactions = c.Interactions
+	t.used = make([]bool, len(c.Interactions))
+	return t, nil
+}
+
+// Recording reports if the Transport is recording.
+func (t *Transport) Recording() bool {
+	return t.mode == Record
+}
+
+// RoundTrip replays the response of the request, or sends and records it.
+func (t *Transport) RoundTrip(hr *http.Request) (*http.Response, error) {
+	if hr.Body != nil {
+		defer hr.Body.Close()
+	}
+	if t.mode == Record {
+		return t.record(hr)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	url := hr.URL.String()
+	for i, in := range t.interactions {
+		if t.used[i] || in.Method != hr.Method || in.URL != url {
+			continue
+		}
+		t.used[i] = true
+		if hr.Body != nil {
+			// Read the body like a server, such as for uploads from a pipe.
+			ioutil.ReadAll(hr.Body)
+		}
+		return in.response(hr), nil
+	}
+	return nil, errors.New("replay: no recorded response for " + hr.Method + " " + url)
+}
+
+func (t *Transport) record(hr *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(hr)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+	in := Interaction{
+		Method: hr.Method,
+		URL:    hr.URL.String(),
+		Status: resp.StatusCode,
+		Header: header,
+		Body:   body,
+	}
+	t.mu.Lock()
+	t.interactions = append(t.interactions, in)
+	t.mu.Unlock()
+	return in.response(hr), nil
+}
+
+// response returns the recorded response to hr.
+func (in Interaction) response(hr *http.Request) *http.Response {
+	body := in.Body
+	length := int64(len(body))
+	if hr.Method == "HEAD" {
+		body = nil
+		length = -1
+		if n, err := strconv.ParseInt(in.Header.Get("Content-Length"), 10, 64); err == nil {
+			length = n
+		}
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(in.Status) + " " + http.StatusText(in.Status),
+		StatusCode:    in.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        in.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: length,
+		Request:       hr,
+	}
+}
+
+// Close writes the cassette when recording.
+func (t *Transport) Close() error {
+	if t.mode != Record {
+		return nil
+	}
+	t.mu.Lock()
+	c := cassette{t.interactions}
+	if c.Interactions == nil {
+		c.Interactions = []Interaction{}
+	}
+	data, err := json.MarshalIndent(c, "", "\t")
+	t.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(t.path, append(data, '\n'), 0644)
+}
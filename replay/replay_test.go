@@ -0,0 +1,92 @@
+package replay_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"paste.run"
+	"paste.run/pastetest"
+	"paste.run/replay"
+)
+
+// session uploads, gets and stats a paste, as recorded and replayed.
+func session(t *testing.T, tr *replay.Transport, baseURL string) (string, string, paste.PasteStats) {
+	t.Helper()
+	client := paste.NewClient(paste.NoConfig(), paste.NoEnv(), paste.BaseURL(baseURL),
+		paste.Token(pastetest.Token), paste.HTTPClient(&http.Client{Transport: tr}))
+	pasteURL, err := client.UploadString("hello\nreplay\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := client.Get(pasteURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadAll(info.Content)
+	info.Content.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Stats sends a HEAD request.
+	ps, err := client.Stats(pasteURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pasteURL, string(content), ps
+}
+
+func TestRecordReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	srv := pastetest.NewServer()
+	tr, err := replay.New(path, replay.Auto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tr.Recording() {
+		t.Fatal("Auto without a cassette is not recording")
+	}
+	recURL, recContent, recStats := session(t, tr, srv.URL)
+	err = tr.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Close() // Replaying doesn't need the server.
+
+	tr, err = replay.New(path, replay.Replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Recording() {
+		t.Fatal("Replay is recording")
+	}
+	pasteURL, content, ps := session(t, tr, srv.URL)
+	if pasteURL != recURL || content != recContent {
+		t.Errorf("replayed %s %q, recorded %s %q", pasteURL, content, recURL, recContent)
+	}
+	if ps != recStats || ps.ByteSize != int64(len(content)) {
+		t.Errorf("replayed stats %+v, recorded %+v", ps, recStats)
+	}
+
+	// Each interaction is replayed once.
+	_, err = paste.Get(pasteURL, paste.HTTPClient(&http.Client{Transport: tr}))
+	if err == nil || !strings.Contains(err.Error(), "replay: no recorded response for GET") {
+		t.Errorf("got error %v, want no recorded response", err)
+	}
+}
+
+func TestNewMissing(t *testing.T) {
+	path := filepath.Join("testdata", "missing.json")
+	if _, err := replay.New(path, replay.Replay); !os.IsNotExist(err) {
+		t.Errorf("Replay of a missing cassette: got %v, want not exist", err)
+	}
+}
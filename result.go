@@ -0,0 +1,41 @@
+package paste
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// UploadResult is the result of UploadInfo.
+type UploadResult struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	RawURL      string    `json:"raw_url"`
+	DeleteToken string    `json:"delete_token,omitempty"` // Deletes the paste without the account token, see DeleteToken
+	Expires     time.Time `json:"expires"`                // IsZero if no expiration
+	Language    string    `json:"language"`               // Detected language
+}
+
+func uploadInfo(r io.Reader, req *request, options ...Option) (UploadResult, error) {
+	req.apply(options)
+	result, err := req.postUpload(r, "application/json")
+	if err != nil {
+		return UploadResult{}, err
+	}
+	var res UploadResult
+	err = json.Unmarshal(result, &res)
+	if err != nil {
+		return UploadResult{}, fmt.Errorf("paste upload: %w", err)
+	}
+	res.URL, err = req.uploadedURL(res.URL)
+	if err != nil {
+		return UploadResult{}, err
+	}
+	return res, nil
+}
+
+// UploadInfo is like Upload, but returns more about the new paste.
+func UploadInfo(r io.Reader, options ...Option) (UploadResult, error) {
+	return uploadInfo(r, &request{}, options...)
+}
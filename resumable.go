@@ -0,0 +1,200 @@
+package paste
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// Resumable uploads the content in chunks of chunkSize bytes.
+// A failed upload can only be resumed with ResumeState,
+// without it nothing is persisted and the upload starts over.
+func Resumable(chunkSize int64) Option {
+	return func(req *request) {
+		req.chunkSize = chunkSize
+	}
+}
+
+// ResumeState persists the state of a Resumable upload in the file at path.
+// If the file exists, the upload is resumed and the same content
+// needs to be uploaded again, the content the server already has is skipped.
+// The file is removed once the upload completes.
+func ResumeState(path string) Option {
+	return func(req *request) {
+		req.resumeState = path
+	}
+}
+
+// resumeState is the persisted state of a Resumable upload.
+type resumeState struct {
+	UploadURL string `json:"upload_url"`
+}
+
+func (req *request) loadResumeState() resumeState {
+	var state resumeState
+	if req.resumeState != "" {
+		if b, err := ioutil.ReadFile(req.resumeState); err == nil {
+			json.Unmarshal(b, &state)
+		}
+	}
+	return state
+}
+
+func (req *request) saveResumeState(state resumeState) error {
+	if req.resumeState == "" {
+		return nil
+	}
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(req.resumeState, b, 0600)
+}
+
+// sendExpect sends hr and returns the response and its body,
+// or an APIError if the status code is not one of codes.
+func (req *request) sendExpect(hr *http.Request, codes ...int) (*http.Response, []byte, error) {
+	resp, err := req.do(hr)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, nil, transportError(hr, err)
+	}
+	for _, code := range codes {
+		if resp.StatusCode == code {
+			return resp, result, nil
+		}
+	}
+	return nil, nil, apiError(resp, result)
+}
+
+// createUpload starts a resumable upload and returns its URL.
+func (req *request) createUpload() (string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	err := req.writeMultipart(w, nil, nil)
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		return "", err
+	}
+	hr, err := req.newRequest("POST", req.endpoint("uploads"), &buf)
+	if err != nil {
+		return "", err
+	}
+	hr.Header.Set("Content-Type", w.FormDataContentType())
+	resp, _, err := req.sendExpect(hr, 201)
+	if err != nil {
+		return "", err
+	}
+	loc, err := resp.Location()
+	if err != nil {
+		return "", errors.New("resumable upload has no Location")
+	}
+	return loc.String(), nil
+}
+
+// uploadOffset returns the bytes the server has of the upload.
+func (req *request) uploadOffset(uploadURL string) (int64, error) {
+	hr, err := req.newRequest("HEAD", uploadURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, _, err := req.sendExpect(hr, 200, 204)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := strconv.ParseInt(resp.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		return 0, errors.New("invalid Upload-Offset")
+	}
+	return offset, nil
+}
+
+// postResumable uploads r in chunks and returns the response body
+// of the final chunk, accept is the Accept header if not empty.
+func (req *request) postResumable(r io.Reader, accept string) ([]byte, error) {
+	if req.files != nil {
+		return nil, errors.New("resumable upload of multiple files is not supported")
+	}
+	if req.encryptKey != nil && req.resumeState != "" {
+		return nil, errors.New("resuming an encrypted upload is not supported")
+	}
+	_, parts, err := req.uploadParts(r)
+	if err != nil {
+		return nil, err
+	}
+	content := parts[0]
+
+	state := req.loadResumeState()
+	var offset int64
+	if state.UploadURL != "" {
+		offset, err = req.uploadOffset(state.UploadURL)
+		if errors.Is(err, ErrNotFound) {
+			state.UploadURL = "" // Expired, start over.
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if state.UploadURL == "" {
+		state.UploadURL, err = req.createUpload()
+		if err != nil {
+			return nil, err
+		}
+		offset = 0
+		err = req.saveResumeState(state)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Skip the content the server already has.
+	_, err = io.CopyN(ioutil.Discard, content, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	chunk := make([]byte, req.chunkSize)
+	for {
+		n, err := io.ReadFull(content, chunk)
+		final := err == io.EOF || err == io.ErrUnexpectedEOF
+		if err != nil && !final {
+			return nil, err
+		}
+		hr, err := req.newRequest("PATCH", state.UploadURL, bytes.NewReader(chunk[:n]))
+		if err != nil {
+			return nil, err
+		}
+		hr.Header.Set("Content-Type", "application/offset+octet-stream")
+		hr.Header.Set("Upload-Offset", strconv.FormatInt(offset, 10))
+		if final {
+			hr.Header.Set("Upload-Complete", "true")
+			if accept != "" {
+				hr.Header.Set("Accept", accept)
+			}
+			_, result, err := req.sendExpect(hr, 201)
+			if err != nil {
+				return nil, err
+			}
+			if req.resumeState != "" {
+				os.Remove(req.resumeState)
+			}
+			return result, nil
+		}
+		_, _, err = req.sendExpect(hr, 204)
+		if err != nil {
+			return nil, err
+		}
+		offset += int64(n)
+	}
+}
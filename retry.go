@@ -0,0 +1,236 @@
+package paste
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy is how to retry failed requests.
+type RetryPolicy struct {
+	MaxAttempts int           // Attempts including the first, retries are disabled if less than 2
+	BaseDelay   time.Duration // Delay before the first retry, doubled for each retry
+	MaxDelay    time.Duration // Maximum delay, or 0 for no maximum
+	Jitter      float64       // Fraction of the delay randomly added or removed, from 0 to 1
+}
+
+// DefaultRetryPolicy is a RetryPolicy for Retry.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+	Jitter:      0.2,
+}
+
+// Retry retries requests which failed from a network error,
+// a 5xx server error, or 429 Too Many Requests, with exponential backoff.
+// A Retry-After response header longer than the backoff is respected,
+// but if it is longer than MaxDelay the request fails, see APIError.RetryAfter.
+// Requests are only retried before their response body is read,
+// so reading the content of Get is not retried, see AutoResume.
+// Uploads are only retried if the paste content is an io.Seeker,
+// such as a file, which is seeked back to send it again.
+// Use RetryBudget to limit the retries while the server is failing.
+func Retry(policy RetryPolicy) Option {
+	return func(req *request) {
+		req.retry = &policy
+	}
+}
+
+// AutoResume makes Get resume reading the content from where it failed,
+// such as after a connection reset, with a Range request.
+// The resumes are limited and delayed by the Retry policy,
+// or DefaultRetryPolicy without Retry. The content is only resumed
+// if the response has an ETag or Last-Modified to check that
+// the paste was not changed, and no Content-Encoding.
+func AutoResume() Option {
+	return func(req *request) {
+		req.autoResume = true
+	}
+}
+
+// delay returns the delay before the retry after attempt.
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(d))
+	}
+	return d
+}
+
+// retryable reports whether the response status can be retried.
+func retryable(code int) bool {
+	return code == http.StatusTooManyRequests ||
+		code >= 500 && code != http.StatusNotImplemented
+}
+
+// retryDelay returns the delay before retrying the request after attempt,
+// or false if it is not to be retried.
+func (req *request) retryDelay(hr *http.Request, attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if req.retry == nil || attempt >= req.retry.MaxAttempts {
+		return 0, false
+	}
+	if hr.Context().Err() != nil {
+		return 0, false
+	}
+	if err == nil && !retryable(resp.StatusCode) {
+		return 0, false
+	}
+	if hr.Body != nil && hr.Body != http.NoBody && hr.GetBody == nil {
+		return 0, false // Can't send the body again.
+	}
+	var ra time.Duration
+	if err == nil {
+		ra, _ = retryAfter(resp.Header.Get("Retry-After"))
+		if req.retry.MaxDelay > 0 && ra > req.retry.MaxDelay {
+			return 0, false // Too long, see APIError.RetryAfter.
+		}
+	}
+	if req.budget != nil && !req.budget.allow() {
+		return 0, false
+	}
+	d := req.retry.delay(attempt)
+	if ra > d {
+		d = ra
+	}
+	return d, true
+}
+
+// retryAfter parses a Retry-After header, in seconds or an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if secs, err := strconv.Atoi(value); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t), true
+	}
+	return 0, false
+}
+
+// retryRequest returns the request to send again, after delay.
+func retryRequest(hr *http.Request, resp *http.Response, delay time.Duration) (*http.Request, error) {
+	if resp != nil {
+		// Drain a little so the connection can be reused.
+		io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4<<10))
+		resp.Body.Close()
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-hr.Context().Done():
+		return nil, hr.Context().Err()
+	}
+
+	if hr.GetBody == nil {
+		return hr, nil
+	}
+	body, err := hr.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	hr = hr.Clone(hr.Context())
+	hr.Body = body
+	return hr, nil
+}
+
+// resumable returns the body of resp, resuming from where reading it
+// failed if possible.
+func (req *request) resumable(pasteURL string, resp *http.Response) io.ReadCloser {
+	validator := resp.Header.Get("ETag")
+	if validator == "" {
+		validator = resp.Header.Get("Last-Modified")
+	}
+	if validator == "" || resp.Header.Get("Content-Encoding") != "" {
+		return resp.Body
+	}
+	policy := req.retry
+	if policy == nil {
+		policy = &DefaultRetryPolicy
+	}
+	ctx := req.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &resumeReader{req: req, pasteURL: pasteURL, validator: validator,
+		policy: policy, ctx: ctx, body: resp.Body, attempt: 1}
+}
+
+// resumeReader reads a response body, and gets the rest of the content
+// with a Range request when reading fails.
+type resumeReader struct {
+	req       *request
+	pasteURL  string
+	validator string // ETag or Last-Modified, for If-Range.
+	policy    *RetryPolicy
+	ctx       context.Context // Of the Get, not its first request with the Timeout.
+
+	body    io.ReadCloser
+	offset  int64 // Of the content read.
+	attempt int
+}
+
+func (r *resumeReader) Read(p []byte) (int, error) {
+	for {
+		n, err := r.body.Read(p)
+		r.offset += int64(n)
+		if err == nil || err == io.EOF || r.attempt >= r.policy.MaxAttempts || r.ctx.Err() != nil {
+			return n, err
+		}
+		if rerr := r.resume(); rerr != nil {
+			return n, err
+		}
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+// resume replaces the body with the content from the offset.
+func (r *resumeReader) resume() error {
+	r.body.Close()
+	t := time.NewTimer(r.policy.delay(r.attempt))
+	defer t.Stop()
+	r.attempt++
+	select {
+	case <-t.C:
+	case <-r.ctx.Done():
+		return r.ctx.Err()
+	}
+
+	hr, err := r.req.newRequest("GET", r.pasteURL, nil)
+	if err != nil {
+		return err
+	}
+	hr.Header.Set("Range", "bytes="+strconv.FormatInt(r.offset, 10)+"-")
+	hr.Header.Set("If-Range", r.validator)
+	hr.Header.Set("Accept-Encoding", "identity")
+	resp, err := r.req.do(hr)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 206 || !strings.HasPrefix(resp.Header.Get("Content-Range"), "bytes "+strconv.FormatInt(r.offset, 10)+"-") {
+		// The paste changed, or the server doesn't support ranges.
+		resp.Body.Close()
+		return errors.New("cannot resume the paste content")
+	}
+	r.body = resp.Body
+	return nil
+}
+
+func (r *resumeReader) Close() error {
+	return r.body.Close()
+}
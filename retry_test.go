@@ -0,0 +1,205 @@
+package paste_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"paste.run"
+)
+
+var retryContent = strings.Repeat("0123456789", 1000)
+
+var fastRetry = paste.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+// failingServer serves retryContent, failing the first requests with fail.
+type failingServer struct {
+	*httptest.Server
+	fail func(w http.ResponseWriter)
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func newFailingServer(failures int, fail func(w http.ResponseWriter)) *failingServer {
+	s := &failingServer{fail: fail}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r)
+		n := len(s.requests)
+		s.mu.Unlock()
+		w.Header().Set("ETag", `"v1"`)
+		if n <= failures {
+			s.fail(w)
+			return
+		}
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader(retryContent))
+	}))
+	return s
+}
+
+func (s *failingServer) get(options ...paste.Option) (string, error) {
+	options = append([]paste.Option{paste.BaseURL(s.URL), paste.HTTPClient(s.Client())}, options...)
+	info, err := paste.Get("abc", options...)
+	if err != nil {
+		return "", err
+	}
+	defer info.Content.Close()
+	b, err := ioutil.ReadAll(info.Content)
+	return string(b), err
+}
+
+// badGateway fails before the body.
+func badGateway(w http.ResponseWriter) {
+	http.Error(w, "bad gateway", http.StatusBadGateway)
+}
+
+// reset fails in the middle of the body.
+func reset(w http.ResponseWriter) {
+	w.Header().Set("Content-Length", "10000")
+	w.Write([]byte(retryContent[:4000]))
+	w.(http.Flusher).Flush()
+	panic(http.ErrAbortHandler)
+}
+
+func TestGetRetryBeforeBody(t *testing.T) {
+	srv := newFailingServer(1, badGateway)
+	defer srv.Close()
+	got, err := srv.get(paste.Retry(fastRetry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != retryContent {
+		t.Errorf("got %d bytes, want %d", len(got), len(retryContent))
+	}
+	if len(srv.requests) != 2 {
+		t.Errorf("got %d requests, want 2", len(srv.requests))
+	}
+}
+
+func TestGetNoRetryBeforeBody(t *testing.T) {
+	srv := newFailingServer(1, badGateway)
+	defer srv.Close()
+	_, err := srv.get()
+	var apiErr *paste.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("got error %v, want a 502 APIError", err)
+	}
+}
+
+func TestGetNoRetryMidBody(t *testing.T) {
+	srv := newFailingServer(1, reset)
+	defer srv.Close()
+	_, err := srv.get(paste.Retry(fastRetry))
+	if err == nil {
+		t.Fatal("got no error reading the reset body")
+	}
+	if len(srv.requests) != 1 {
+		t.Errorf("got %d requests, want 1 without AutoResume", len(srv.requests))
+	}
+}
+
+func TestGetAutoResumeMidBody(t *testing.T) {
+	srv := newFailingServer(2, reset)
+	defer srv.Close()
+	srv.fail = func(w http.ResponseWriter) {
+		if len(srv.requests) == 1 {
+			reset(w)
+		}
+		// The resume fails before the body, and is retried.
+		badGateway(w)
+	}
+	got, err := srv.get(paste.Retry(fastRetry), paste.AutoResume())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != retryContent {
+		t.Errorf("got %d bytes, want %d", len(got), len(retryContent))
+	}
+	if len(srv.requests) != 3 {
+		t.Fatalf("got %d requests, want 3", len(srv.requests))
+	}
+	last := srv.requests[2]
+	if r := last.Header.Get("Range"); r != "bytes=4000-" {
+		t.Errorf("resume Range = %q, want %q", r, "bytes=4000-")
+	}
+	if r := last.Header.Get("If-Range"); r != `"v1"` {
+		t.Errorf("resume If-Range = %q, want %q", r, `"v1"`)
+	}
+}
+
+func TestGetAutoResumeChanged(t *testing.T) {
+	srv := newFailingServer(1, reset)
+	defer srv.Close()
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv.mu.Lock()
+		srv.requests = append(srv.requests, r)
+		n := len(srv.requests)
+		srv.mu.Unlock()
+		if n == 1 {
+			w.Header().Set("ETag", `"v1"`)
+			reset(w)
+		}
+		// Changed, so the If-Range doesn't match.
+		w.Header().Set("ETag", `"v2"`)
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader([]byte("changed")))
+	})
+	got, err := srv.get(paste.Retry(fastRetry), paste.AutoResume())
+	if err == nil {
+		t.Fatalf("got %q and no error for a changed paste", got)
+	}
+	if len(srv.requests) != 2 {
+		t.Errorf("got %d requests, want 2", len(srv.requests))
+	}
+}
+
+func TestRetryAfterDate(t *testing.T) {
+	retryAt := time.Now().Add(2 * time.Second).UTC()
+	srv := newFailingServer(1, func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", retryAt.Format(http.TimeFormat))
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+	defer srv.Close()
+	start := time.Now()
+	_, err := srv.get(paste.Retry(fastRetry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The date has a resolution of a second.
+	if d := time.Since(start); d < time.Second {
+		t.Errorf("retried after %v, want the Retry-After date", d)
+	}
+
+	retryAt = time.Now().Add(time.Hour).UTC()
+	apiErr := &paste.APIError{Headers: http.Header{"Retry-After": {retryAt.Format(http.TimeFormat)}}}
+	if d := apiErr.RetryAfter(); d < 59*time.Minute || d > time.Hour {
+		t.Errorf("APIError.RetryAfter = %v, want an hour", d)
+	}
+}
+
+func TestRetryAfterMaxDelay(t *testing.T) {
+	for _, value := range []string{"86400", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)} {
+		srv := newFailingServer(1, func(w http.ResponseWriter) {
+			w.Header().Set("Retry-After", value)
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		})
+		policy := fastRetry
+		policy.MaxDelay = time.Second
+		start := time.Now()
+		_, err := srv.get(paste.Retry(policy))
+		srv.Close()
+		if d := time.Since(start); d > policy.MaxDelay {
+			t.Errorf("Retry-After %s: returned after %v", value, d)
+		}
+		var apiErr *paste.APIError
+		if !errors.As(err, &apiErr) || apiErr.RetryAfter() < time.Minute {
+			t.Errorf("Retry-After %s: got error %v, want an APIError with the Retry-After", value, err)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package paste
+
+import (
+	"net/url"
+	"time"
+)
+
+// CreatedBetween filters Search results to pastes created in the range,
+// a zero time leaves that end of the range open.
+func CreatedBetween(from, to time.Time) Option {
+	return func(req *request) {
+		req.createdFrom = from
+		req.createdTo = to
+	}
+}
+
+func search(query string, req *request, options ...Option) ([]PasteSummary, error) {
+	req.apply(options)
+
+	q := url.Values{"q": {query}}
+	if req.typ != "" {
+		q.Set("lang", req.typ)
+	}
+	if req.author != "" {
+		q.Set("author", req.author)
+	}
+	if !req.createdFrom.IsZero() {
+		q.Set("from", req.createdFrom.UTC().Format(time.RFC3339))
+	}
+	if !req.createdTo.IsZero() {
+		q.Set("to", req.createdTo.UTC().Format(time.RFC3339))
+	}
+	list, err := req.getList(req.endpoint("search") + req.listQuery(q))
+	if err != nil {
+		return nil, err
+	}
+	return list.Pastes, nil
+}
+
+// Search finds pastes matching query.
+// Filter the results by language with Type, by author with Author,
+// by creation time with CreatedBetween, and by tag with ByTag.
+// Use Limit for more results.
+func Search(query string, options ...Option) ([]PasteSummary, error) {
+	return search(query, &request{}, options...)
+}
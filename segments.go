@@ -0,0 +1,139 @@
+package paste
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strconv"
+)
+
+// segmentMinSize is the minimum size of a paste to get in segments.
+const segmentMinSize = 1 << 20
+
+// ParallelSegments gets the content of large pastes with n concurrent
+// Range requests, which can be faster on high-latency links.
+// The content is held in memory. Get falls back to a single request
+// if the server doesn't support ranges.
+func ParallelSegments(n int) Option {
+	return func(req *request) {
+		req.segments = n
+	}
+}
+
+// segment is a part of the content being downloaded.
+type segment struct {
+	data []byte
+	err  error
+	done chan struct{}
+}
+
+// segmentsReader reads the segments in order as they are downloaded.
+type segmentsReader struct {
+	segments []*segment
+	cancel   context.CancelFunc
+}
+
+func (sr *segmentsReader) Read(p []byte) (int, error) {
+	for len(sr.segments) != 0 {
+		s := sr.segments[0]
+		<-s.done
+		if s.err != nil {
+			return 0, s.err
+		}
+		if len(s.data) != 0 {
+			n := copy(p, s.data)
+			s.data = s.data[n:]
+			return n, nil
+		}
+		sr.segments = sr.segments[1:]
+	}
+	return 0, io.EOF
+}
+
+func (sr *segmentsReader) Close() error {
+	sr.cancel()
+	return nil
+}
+
+// getSegmented gets the paste content in segments,
+// ok is false if the paste can't be got in segments.
+func (req *request) getSegmented(pasteURL string) (info PasteInfo, ok bool, err error) {
+	hr, err := req.newRequest("HEAD", pasteURL, nil)
+	if err != nil {
+		return PasteInfo{}, false, err
+	}
+	resp, err := req.do(hr)
+	if err != nil {
+		return PasteInfo{}, false, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 200 || resp.Header.Get("Accept-Ranges") != "bytes" ||
+		resp.ContentLength < segmentMinSize {
+		return PasteInfo{}, false, nil
+	}
+	info = pasteInfo(resp)
+	if req.maxSize > 0 && info.Size > req.maxSize {
+		return PasteInfo{}, false, ErrTooLarge
+	}
+	// Fail the segments if the paste changes.
+	ifRange := resp.Header.Get("ETag")
+	if ifRange == "" {
+		ifRange = resp.Header.Get("Last-Modified")
+	}
+	if ifRange == "" {
+		return PasteInfo{}, false, nil
+	}
+
+	ctx, cancel := context.WithCancel(hr.Context())
+	sr := &segmentsReader{cancel: cancel}
+	size := (info.Size + int64(req.segments) - 1) / int64(req.segments)
+	for start := int64(0); start < info.Size; start += size {
+		end := start + size - 1
+		if end >= info.Size {
+			end = info.Size - 1
+		}
+		s := &segment{done: make(chan struct{})}
+		sr.segments = append(sr.segments, s)
+		go func(start, end int64) {
+			defer close(s.done)
+			s.data, s.err = req.getSegment(ctx, pasteURL, ifRange, start, end)
+		}(start, end)
+	}
+	info.Content = sr
+	return info, true, nil
+}
+
+// getSegment gets the content from start to end inclusive.
+func (req *request) getSegment(ctx context.Context, pasteURL, ifRange string, start, end int64) ([]byte, error) {
+	hr, err := req.newRequest("GET", pasteURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	hr = hr.WithContext(ctx)
+	hr.Header.Set("Range", "bytes="+strconv.FormatInt(start, 10)+"-"+strconv.FormatInt(end, 10))
+	hr.Header.Set("If-Range", ifRange)
+	resp, err := req.do(hr)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 206 {
+		result, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, transportError(hr, err)
+		}
+		if resp.StatusCode == 200 {
+			return nil, errors.New("paste changed while getting segments")
+		}
+		return nil, apiError(resp, result)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, end-start+1))
+	if err != nil {
+		return nil, transportError(hr, err)
+	}
+	if int64(len(data)) != end-start+1 {
+		return nil, transportError(hr, io.ErrUnexpectedEOF)
+	}
+	return data, nil
+}
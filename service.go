@@ -0,0 +1,15 @@
+package paste
+
+import "io"
+
+// PasteService is the main paste operations, implemented by Client.
+// Depend on it rather than on Client to replace the paste layer in tests,
+// such as with pastetest.Fake.
+type PasteService interface {
+	Upload(r io.Reader, options ...Option) (string, error)
+	Get(paste string, options ...Option) (PasteInfo, error)
+	Delete(paste string, options ...Option) error
+	GetLanguages(options ...Option) ([]LanguageInfo, error)
+}
+
+var _ PasteService = (*Client)(nil)
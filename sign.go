@@ -0,0 +1,56 @@
+package paste
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func signedURL(paste string, ttl time.Duration, req *request, options ...Option) (string, error) {
+	req.apply(options)
+
+	if ttl < time.Second {
+		return "", errors.New("signed URL ttl must be at least 1 second")
+	}
+	if req.tok == "" {
+		return "", errors.New("signed URL requires a token")
+	}
+	id, err := pasteID(paste)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{"ttl": {strconv.FormatInt(int64(ttl/time.Second), 10)}}
+	hr, err := req.newRequest("POST", req.endpoint(id+"/sign"), strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+
+	hr.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := req.do(hr)
+	if err != nil {
+		return "", err
+	}
+	result, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return "", transportError(hr, err)
+	}
+	if resp.StatusCode != 200 && resp.StatusCode != 201 {
+		// Such as the ttl exceeding the server maximum.
+		return "", apiError(resp, result)
+	}
+	return strings.TrimSpace(string(result)), nil
+}
+
+// SignedURL creates a URL to a private paste which is valid for ttl,
+// allowing access without a token. The ttl is in whole seconds,
+// and the server can reject a ttl over its maximum.
+// Requires the Token of the paste owner.
+func SignedURL(paste string, ttl time.Duration, options ...Option) (string, error) {
+	return signedURL(paste, ttl, &request{}, options...)
+}
@@ -0,0 +1,44 @@
+package paste
+
+func stat(paste string, req *request, options ...Option) (PasteInfo, error) {
+	req.apply(options)
+
+	paste, err := req.splitKey(paste)
+	if err != nil {
+		return PasteInfo{}, err
+	}
+	id, err := pasteID(paste)
+	if err != nil {
+		return PasteInfo{}, err
+	}
+	hr, err := req.newRequest("HEAD", req.endpoint(id)+"?raw", nil)
+	if err != nil {
+		return PasteInfo{}, err
+	}
+
+	req.setConditions(hr)
+
+	resp, err := req.do(hr)
+	if err != nil {
+		return PasteInfo{}, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == 304 {
+		return PasteInfo{}, ErrNotModified
+	}
+	if resp.StatusCode != 200 {
+		return PasteInfo{}, apiError(resp, nil)
+	}
+	info := pasteInfo(resp)
+	if req.decryptKey != nil {
+		info.Size = -1 // Decrypted size is unknown.
+	}
+	return info, nil
+}
+
+// Stat gets the paste information without the content,
+// the PasteInfo Content is nil.
+// Use errors.Is(err, ErrNotFound) to check if the paste exists.
+func Stat(paste string, options ...Option) (PasteInfo, error) {
+	return stat(paste, &request{}, options...)
+}
@@ -0,0 +1,127 @@
+package paste
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"time"
+)
+
+// PasteStats is the size information of a paste.
+type PasteStats struct {
+	ByteSize      int64  `json:"byte_size"`  // -1 if unknown
+	LineCount     int64  `json:"line_count"` // -1 if unknown
+	LineEstimated bool   `json:"line_estimated,omitempty"`
+	Language      string `json:"language"`
+}
+
+// statsSampleSize is the number of bytes read to estimate the line count.
+const statsSampleSize = 64 << 10
+
+func stats(paste string, req *request, options ...Option) (PasteStats, error) {
+	req.apply(options)
+
+	id, err := pasteID(paste)
+	if err != nil {
+		return PasteStats{}, err
+	}
+	pasteURL := req.endpoint(id) + "?raw"
+	hr, err := req.newRequest("HEAD", pasteURL, nil)
+	if err != nil {
+		return PasteStats{}, err
+	}
+
+	resp, err := req.do(hr)
+	if err != nil {
+		return PasteStats{}, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return PasteStats{}, apiError(resp, nil)
+	}
+	ps := PasteStats{
+		ByteSize:  resp.ContentLength,
+		LineCount: -1,
+		Language:  resp.Header.Get("Paste-Language"),
+	}
+	if lines, err := strconv.ParseInt(resp.Header.Get("X-Paste-Lines"), 10, 64); err == nil {
+		ps.LineCount = lines
+		return ps, nil
+	}
+
+	// Estimate the line count from the start of the content.
+	hr, err = req.newRequest("GET", pasteURL, nil)
+	if err != nil {
+		return PasteStats{}, err
+	}
+	hr.Header.Set("Range", "bytes=0-"+strconv.Itoa(statsSampleSize-1))
+	resp, err = req.do(hr)
+	if err != nil {
+		return PasteStats{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 && resp.StatusCode != 206 {
+		result, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return PasteStats{}, transportError(hr, err)
+		}
+		return PasteStats{}, apiError(resp, result)
+	}
+	sample, err := ioutil.ReadAll(io.LimitReader(resp.Body, statsSampleSize))
+	if err != nil {
+		return PasteStats{}, transportError(hr, err)
+	}
+	lines := int64(bytes.Count(sample, []byte{'\n'}))
+	if len(sample) > 0 && sample[len(sample)-1] != '\n' {
+		lines++
+	}
+	ps.LineCount = lines
+	// The count is exact only if the sample is all the content.
+	if len(sample) == statsSampleSize && ps.ByteSize != statsSampleSize {
+		ps.LineEstimated = true
+		if ps.ByteSize > int64(len(sample)) {
+			ps.LineCount = lines * ps.ByteSize / int64(len(sample))
+		}
+	}
+	return ps, nil
+}
+
+// Stats gets the size information of a paste without downloading it.
+// If the server does not report the line count,
+// it is estimated from the start of the content.
+func Stats(paste string, options ...Option) (PasteStats, error) {
+	return stats(paste, &request{}, options...)
+}
+
+// ViewStats is the view statistics of a paste, see GetStats.
+type ViewStats struct {
+	Views         int64     `json:"views"`
+	UniqueViewers int64     `json:"unique_viewers"`
+	LastAccessed  time.Time `json:"last_accessed"` // IsZero if never viewed
+}
+
+func getStats(paste string, req *request, options ...Option) (ViewStats, error) {
+	req.apply(options)
+
+	if req.tok == "" {
+		return ViewStats{}, errors.New("GetStats requires a token")
+	}
+	id, err := pasteID(paste)
+	if err != nil {
+		return ViewStats{}, err
+	}
+	var vs ViewStats
+	err = req.doJSON("GET", req.endpoint(id+"/views"), nil, &vs)
+	if err != nil {
+		return ViewStats{}, err
+	}
+	return vs, nil
+}
+
+// GetStats gets the view statistics of a paste, such as to know
+// if a shared link was opened. Requires the Token of the paste owner.
+func GetStats(paste string, options ...Option) (ViewStats, error) {
+	return getStats(paste, &request{}, options...)
+}
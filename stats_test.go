@@ -0,0 +1,45 @@
+package paste_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"paste.run"
+)
+
+func TestStatsUnknownSize(t *testing.T) {
+	var content string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "HEAD" {
+			return // No Content-Length.
+		}
+		// Chunked, with no Content-Range.
+		w.(http.Flusher).Flush()
+		w.Write([]byte(content))
+	}))
+	defer srv.Close()
+
+	for _, test := range []struct {
+		content   string
+		lines     int64
+		estimated bool
+	}{
+		{"a\nb\nc", 3, false},
+		{strings.Repeat("line\n", 20000), 64 << 10 / 5, true},
+	} {
+		content = test.content
+		ps, err := paste.Stats("abc", paste.BaseURL(srv.URL), paste.HTTPClient(srv.Client()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ps.ByteSize != -1 {
+			t.Errorf("ByteSize = %d, want -1", ps.ByteSize)
+		}
+		if ps.LineCount < test.lines || ps.LineCount > test.lines+1 || ps.LineEstimated != test.estimated {
+			t.Errorf("%d bytes: LineCount %d, LineEstimated %v; want %d, %v",
+				len(test.content), ps.LineCount, ps.LineEstimated, test.lines, test.estimated)
+		}
+	}
+}
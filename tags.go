@@ -0,0 +1,36 @@
+package paste
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Tags of the paste for upload.
+// Tags cannot contain a comma.
+func Tags(tags ...string) Option {
+	for _, tag := range tags {
+		if strings.Contains(tag, ",") {
+			panic("invalid tag")
+		}
+	}
+	return func(req *request) {
+		req.tags = tags
+	}
+}
+
+// ByTag filters ListPastes and Search results to pastes with the tag.
+func ByTag(tag string) Option {
+	return func(req *request) {
+		req.byTag = tag
+	}
+}
+
+func parseTags(h http.Header) []string {
+	var result []string
+	for _, tag := range strings.Split(h.Get("Paste-Tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
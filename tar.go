@@ -0,0 +1,38 @@
+package paste
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"io"
+)
+
+func getTar(paste string, req *request, options ...Option) (*tar.Reader, PasteInfo, func() error, error) {
+	info, err := get(paste, req, options...)
+	if err != nil {
+		return nil, PasteInfo{}, nil, err
+	}
+	br := bufio.NewReader(info.Content)
+	var r io.Reader = br
+	closeFunc := info.Content.Close
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			info.Content.Close()
+			return nil, PasteInfo{}, nil, err
+		}
+		r = zr
+		closeFunc = func() error {
+			zr.Close()
+			return info.Content.Close()
+		}
+	}
+	return tar.NewReader(r), info, closeFunc, nil
+}
+
+// GetTar gets a tar paste, which can be gzip compressed, as a tar.Reader.
+// The returned close func needs to be called when done,
+// instead of closing PasteInfo.Content.
+func GetTar(paste string, options ...Option) (*tar.Reader, PasteInfo, func() error, error) {
+	return getTar(paste, &request{}, options...)
+}
@@ -0,0 +1,115 @@
+package paste
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// minThroughputReader closes the content if less than min bytes
+// are read during a window of time spent in Read.
+type minThroughputReader struct {
+	rc     io.ReadCloser
+	min    int64
+	window time.Duration
+
+	mu      sync.Mutex
+	n       int64         // Bytes read in the current window.
+	spent   time.Duration // Time in the previous Reads of the current window.
+	start   time.Time     // Of the current Read, IsZero if not reading.
+	started bool          // Content received, the windows started.
+	timer   *time.Timer   // Ends the window during a Read.
+	err     error
+	closed  bool
+}
+
+func newMinThroughputReader(rc io.ReadCloser, bytesPerSec int64, window time.Duration) *minThroughputReader {
+	if window <= 0 {
+		window = time.Second
+	}
+	return &minThroughputReader{
+		rc:     rc,
+		min:    int64(float64(bytesPerSec) * window.Seconds()),
+		window: window,
+	}
+}
+
+func (mr *minThroughputReader) Read(p []byte) (int, error) {
+	mr.mu.Lock()
+	if mr.err != nil {
+		mr.mu.Unlock()
+		return 0, mr.err
+	}
+	if mr.started && !mr.closed {
+		mr.start = time.Now()
+		if mr.timer == nil {
+			mr.timer = time.AfterFunc(mr.window-mr.spent, mr.check)
+		} else {
+			mr.timer.Reset(mr.window - mr.spent)
+		}
+	}
+	mr.mu.Unlock()
+
+	n, err := mr.rc.Read(p)
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	if mr.err != nil {
+		return n, mr.err
+	}
+	if !mr.start.IsZero() {
+		mr.timer.Stop()
+		mr.spent += time.Since(mr.start)
+		mr.start = time.Time{}
+	}
+	mr.n += int64(n)
+	mr.started = mr.started || n > 0
+	if err != nil {
+		mr.stop()
+		return n, err
+	}
+	if mr.spent >= mr.window {
+		if mr.n < mr.min {
+			mr.err = ErrTooSlow
+			mr.stop()
+			return n, mr.err
+		}
+		mr.n, mr.spent = 0, 0
+	}
+	return n, nil
+}
+
+// check ends the window during a Read.
+func (mr *minThroughputReader) check() {
+	mr.mu.Lock()
+	if mr.closed || mr.start.IsZero() || mr.spent+time.Since(mr.start) < mr.window {
+		mr.mu.Unlock()
+		return // Stale.
+	}
+	if mr.n < mr.min {
+		mr.err = ErrTooSlow
+		mr.stop()
+		mr.mu.Unlock()
+		mr.rc.Close() // Unblock the stalled Read.
+		return
+	}
+	mr.n, mr.spent = 0, 0
+	mr.start = time.Now()
+	mr.timer.Reset(mr.window)
+	mr.mu.Unlock()
+}
+
+// stop the timer; must hold mu.
+func (mr *minThroughputReader) stop() {
+	mr.closed = true
+	if mr.timer != nil {
+		mr.timer.Stop()
+	}
+}
+
+func (mr *minThroughputReader) Close() error {
+	mr.mu.Lock()
+	mr.stop()
+	mr.mu.Unlock()
+	return mr.rc.Close()
+}
@@ -0,0 +1,65 @@
+package paste
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stallingReader returns its content, then blocks until closed.
+type stallingReader struct {
+	r      io.Reader
+	closed chan struct{}
+}
+
+func (sr *stallingReader) Read(p []byte) (int, error) {
+	n, err := sr.r.Read(p)
+	if err == io.EOF {
+		<-sr.closed
+		return 0, io.ErrClosedPipe
+	}
+	return n, err
+}
+
+func (sr *stallingReader) Close() error {
+	select {
+	case <-sr.closed:
+	default:
+		close(sr.closed)
+	}
+	return nil
+}
+
+func TestMinThroughputStalled(t *testing.T) {
+	sr := &stallingReader{strings.NewReader("hello"), make(chan struct{})}
+	mr := newMinThroughputReader(sr, 1000, 20*time.Millisecond)
+	_, err := ioutil.ReadAll(mr)
+	if err != ErrTooSlow {
+		t.Errorf("got %v, want ErrTooSlow", err)
+	}
+}
+
+func TestMinThroughputSlowReader(t *testing.T) {
+	content := strings.Repeat("x", 100)
+	mr := newMinThroughputReader(ioutil.NopCloser(strings.NewReader(content)), 1000, 20*time.Millisecond)
+	defer mr.Close()
+	// The time between the Reads doesn't count.
+	p := make([]byte, 10)
+	var got []byte
+	for {
+		n, err := mr.Read(p)
+		got = append(got, p[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if string(got) != content {
+		t.Errorf("got %q, want %q", got, content)
+	}
+}
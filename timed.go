@@ -0,0 +1,71 @@
+package paste
+
+import (
+	"io"
+	"time"
+)
+
+// DownloadStats are timings of a paste download.
+// Read them only after the content is fully read or closed.
+type DownloadStats struct {
+	Start    time.Time     // When the request started
+	TTFB     time.Duration // Time to the first byte of content
+	Duration time.Duration // Time to the end of content, or Close
+	Bytes    int64         // Content bytes read
+}
+
+// Throughput returns the bytes per second read.
+func (s *DownloadStats) Throughput() float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+	return float64(s.Bytes) / s.Duration.Seconds()
+}
+
+type timedReader struct {
+	rc    io.ReadCloser
+	stats *DownloadStats
+	done  bool
+}
+
+func (tr *timedReader) Read(p []byte) (int, error) {
+	n, err := tr.rc.Read(p)
+	if n > 0 && tr.stats.Bytes == 0 {
+		tr.stats.TTFB = time.Since(tr.stats.Start)
+	}
+	tr.stats.Bytes += int64(n)
+	if err == io.EOF {
+		tr.finish()
+	}
+	return n, err
+}
+
+func (tr *timedReader) Close() error {
+	tr.finish()
+	return tr.rc.Close()
+}
+
+func (tr *timedReader) finish() {
+	if !tr.done {
+		tr.done = true
+		tr.stats.Duration = time.Since(tr.stats.Start)
+	}
+}
+
+func getTimed(paste string, req *request, options ...Option) (io.ReadCloser, *DownloadStats, PasteInfo, error) {
+	stats := &DownloadStats{Start: time.Now()}
+	info, err := get(paste, req, options...)
+	if err != nil {
+		return nil, nil, PasteInfo{}, err
+	}
+	r := &timedReader{rc: info.Content, stats: stats}
+	info.Content = r
+	return r, stats, info, nil
+}
+
+// GetTimed is like Get, but also returns DownloadStats
+// which are updated as the returned content reader is read.
+// The returned reader is also the PasteInfo Content.
+func GetTimed(paste string, options ...Option) (io.ReadCloser, *DownloadStats, PasteInfo, error) {
+	return getTimed(paste, &request{}, options...)
+}
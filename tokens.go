@@ -0,0 +1,80 @@
+package paste
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+// APIToken is an API token of the account, see CreateToken.
+type APIToken struct {
+	ID      string    `json:"id"`
+	Token   string    `json:"token"` // Secret token, only set when created
+	Scopes  []string  `json:"scopes"`
+	Expires time.Time `json:"expires"` // IsZero if no expiration
+}
+
+func createToken(scopes []string, expiry time.Duration, req *request, options ...Option) (APIToken, error) {
+	req.apply(options)
+
+	if req.tok == "" {
+		return APIToken{}, errors.New("creating a token requires a token")
+	}
+	if expiry < 0 {
+		return APIToken{}, errors.New("negative token expiry")
+	}
+	in := struct {
+		Scopes    []string `json:"scopes"`
+		ExpiresIn int64    `json:"expires_in,omitempty"` // Seconds
+	}{scopes, int64(expiry / time.Second)}
+	var token APIToken
+	err := req.doJSON("POST", req.endpoint("tokens"), in, &token)
+	if err != nil {
+		return APIToken{}, err
+	}
+	return token, nil
+}
+
+// CreateToken creates a new API token with the scopes, such as "upload",
+// which expires after expiry, or never if 0.
+// The scopes cannot exceed the scopes of the Token creating it.
+func CreateToken(scopes []string, expiry time.Duration, options ...Option) (APIToken, error) {
+	return createToken(scopes, expiry, &request{}, options...)
+}
+
+func rotateToken(req *request, options ...Option) (APIToken, error) {
+	req.apply(options)
+
+	if req.tok == "" {
+		return APIToken{}, errors.New("rotating a token requires a token")
+	}
+	var token APIToken
+	err := req.doJSON("POST", req.endpoint("tokens/current/rotate"), nil, &token)
+	if err != nil {
+		return APIToken{}, err
+	}
+	return token, nil
+}
+
+// RotateToken replaces the Token with a new token with the same scopes,
+// and revokes the old token.
+func RotateToken(options ...Option) (APIToken, error) {
+	return rotateToken(&request{}, options...)
+}
+
+func revokeToken(id string, req *request, options ...Option) error {
+	req.apply(options)
+
+	if req.tok == "" {
+		return errors.New("revoking a token requires a token")
+	}
+	if id == "" {
+		return errors.New("empty token ID")
+	}
+	return req.doJSON("DELETE", req.endpoint("tokens/"+url.PathEscape(id)), nil, nil)
+}
+
+// RevokeToken revokes the API token with the ID.
+func RevokeToken(id string, options ...Option) error {
+	return revokeToken(id, &request{}, options...)
+}
@@ -0,0 +1,101 @@
+package paste
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// parseTOML parses the subset of TOML used by the config file:
+// tables, and keys with string, integer and boolean values.
+// It returns the values by table name, "" for the keys before any table.
+func parseTOML(r io.Reader) (map[string]map[string]string, error) {
+	tables := map[string]map[string]string{"": {}}
+	table := ""
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			end := strings.IndexByte(line, ']')
+			if end == -1 || strings.HasPrefix(line, "[[") || !isTOMLComment(line[end+1:]) {
+				return nil, fmt.Errorf("line %d: invalid table", n)
+			}
+			table = strings.TrimSpace(line[1:end])
+			if table == "" {
+				return nil, fmt.Errorf("line %d: invalid table", n)
+			}
+			if _, ok := tables[table]; !ok {
+				tables[table] = map[string]string{}
+			}
+			continue
+		}
+		eq := strings.IndexByte(line, '=')
+		if eq == -1 {
+			return nil, fmt.Errorf("line %d: expected key = value", n)
+		}
+		key := strings.TrimSpace(line[:eq])
+		if key == "" || strings.ContainsAny(key, " \t\"'") {
+			return nil, fmt.Errorf("line %d: invalid key", n)
+		}
+		value, err := parseTOMLValue(strings.TrimSpace(line[eq+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		if _, ok := tables[table][key]; ok {
+			return nil, fmt.Errorf("line %d: duplicate key %s", n, key)
+		}
+		tables[table][key] = value
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+func isTOMLComment(s string) bool {
+	s = strings.TrimSpace(s)
+	return s == "" || s[0] == '#'
+}
+
+// parseTOMLValue returns a string, integer or boolean value as a string.
+func parseTOMLValue(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				if !isTOMLComment(s[i+1:]) {
+					return "", fmt.Errorf("unexpected text after value")
+				}
+				return strconv.Unquote(s[:i+1])
+			}
+		}
+		return "", fmt.Errorf("unterminated string")
+	case strings.HasPrefix(s, "'"):
+		end := strings.IndexByte(s[1:], '\'')
+		if end == -1 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		if !isTOMLComment(s[end+2:]) {
+			return "", fmt.Errorf("unexpected text after value")
+		}
+		return s[1 : end+1], nil
+	}
+	if i := strings.IndexByte(s, '#'); i != -1 {
+		s = strings.TrimSpace(s[:i])
+	}
+	if s == "true" || s == "false" {
+		return s, nil
+	}
+	if _, err := strconv.ParseInt(strings.Replace(s, "_", "", -1), 10, 64); err == nil {
+		return strings.Replace(s, "_", "", -1), nil
+	}
+	return "", fmt.Errorf("unsupported value %q", s)
+}
@@ -0,0 +1,103 @@
+package paste
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Tracer traces the uploads and gets, see WithTracer.
+// The module has no dependencies, so WithTracer takes a Tracer rather than
+// an OpenTelemetry TracerProvider. A Tracer of a TracerProvider tp is:
+//
+//	type otelTracer struct{ trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, func(map[string]interface{}, error)) {
+//		ctx, span := t.Tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+//		return ctx, func(attrs map[string]interface{}, err error) {
+//			for key, value := range attrs {
+//				switch value := value.(type) {
+//				case string:
+//					span.SetAttributes(attribute.String(key, value))
+//				case int:
+//					span.SetAttributes(attribute.Int(key, value))
+//				case int64:
+//					span.SetAttributes(attribute.Int64(key, value))
+//				}
+//			}
+//			if err != nil {
+//				span.RecordError(err)
+//				span.SetStatus(codes.Error, err.Error())
+//			}
+//			span.End()
+//		}
+//	}
+//
+//	func (otelTracer) Inject(ctx context.Context, h http.Header) {
+//		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(h))
+//	}
+//
+//	client := paste.NewClient(paste.WithTracer(otelTracer{tp.Tracer("paste.run")}))
+type Tracer interface {
+	// Start starts a span named name, such as "paste.Upload",
+	// and returns the context of the span and the func to end it
+	// with the attributes and the error, if any. The attributes are
+	// paste.id (a string), paste.size (an int64, -1 if unknown)
+	// and http.status_code (an int).
+	Start(ctx context.Context, name string) (context.Context, func(attrs map[string]interface{}, err error))
+
+	// Inject adds the trace context of ctx to the request headers,
+	// such as traceparent.
+	Inject(ctx context.Context, h http.Header)
+}
+
+// WithTracer creates a span for each Upload and Get with t,
+// and propagates the trace context to the server.
+func WithTracer(t Tracer) Option {
+	return func(req *request) {
+		req.tracer = t
+	}
+}
+
+// startSpan starts a span of the operation if there is a Tracer,
+// and returns the func to end it with the paste ID, size and error.
+func (req *request) startSpan(name string) func(id string, size int64, err error) {
+	if req.tracer == nil {
+		return func(string, int64, error) {}
+	}
+	ctx := req.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, end := req.tracer.Start(ctx, name)
+	req.ctx = ctx
+
+	tracer := req.tracer
+	req.onRequest = append(req.onRequest[:len(req.onRequest):len(req.onRequest)], func(hr *http.Request) {
+		tracer.Inject(hr.Context(), hr.Header)
+	})
+	status := 0
+	onAttempt(func(hr *http.Request, attempt int, resp *http.Response, err error, d time.Duration) {
+		if resp != nil {
+			status = resp.StatusCode
+		}
+	})(req)
+
+	return func(id string, size int64, err error) {
+		attrs := map[string]interface{}{"paste.size": size}
+		if id != "" {
+			attrs["paste.id"] = id
+		}
+		if status != 0 {
+			attrs["http.status_code"] = status
+		}
+		end(attrs, err)
+	}
+}
+
+// urlID returns the paste ID of a paste URL.
+func urlID(pasteURL string) string {
+	pasteURL = strings.SplitN(pasteURL, "#", 2)[0]
+	return pasteURL[strings.LastIndexByte(pasteURL, '/')+1:]
+}
@@ -0,0 +1,209 @@
+package paste
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// transportKey is the transport settings of a request,
+// requests with the same settings share a http.Client.
+type transportKey struct {
+	proxy      string
+	tlsConfig  *tls.Config
+	clientCert *tls.Certificate
+	rootCAs    *x509.CertPool
+	pins       *certPins
+	dialer     *dialer
+}
+
+// maxClients is the number of transports kept for reuse,
+// the least recently used one is closed when there are more.
+const maxClients = 16
+
+var (
+	clientsMu sync.Mutex
+	clients   = map[transportKey]*list.Element{}
+	clientLRU = list.New() // Of *transportEntry, most recently used first.
+)
+
+type transportEntry struct {
+	key    transportKey
+	client *http.Client
+}
+
+// httpClient returns the http.Client for the request.
+func (req *request) httpClient() *http.Client {
+	if req.client != nil {
+		return req.client
+	}
+	key := transportKey{req.proxy, req.tlsConfig, req.clientCert, req.rootCAs, req.pins, req.dialer}
+	if key == (transportKey{}) {
+		return http.DefaultClient
+	}
+
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	if e, ok := clients[key]; ok {
+		clientLRU.MoveToFront(e)
+		return e.Value.(*transportEntry).client
+	}
+	client := &http.Client{Transport: newTransport(key)}
+	clients[key] = clientLRU.PushFront(&transportEntry{key, client})
+	if clientLRU.Len() > maxClients {
+		old := clientLRU.Remove(clientLRU.Back()).(*transportEntry)
+		delete(clients, old.key)
+		// Requests in progress keep their connections.
+		old.client.CloseIdleConnections()
+	}
+	return client
+}
+
+func newTransport(key transportKey) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if key.proxy != "" {
+		proxyURL, _ := url.Parse(key.proxy) // Validated by Proxy.
+		t.Proxy = http.ProxyURL(proxyURL)
+	}
+	if key.tlsConfig != nil {
+		t.TLSClientConfig = key.tlsConfig
+	}
+	if key.dialer != nil {
+		t.DialContext = key.dialer.dial
+	}
+	if key.clientCert != nil || key.rootCAs != nil || key.pins != nil {
+		cfg := key.tlsConfig.Clone()
+		if cfg == nil {
+			cfg = &tls.Config{}
+		}
+		if key.clientCert != nil {
+			cfg.Certificates = []tls.Certificate{*key.clientCert}
+		}
+		if key.rootCAs != nil {
+			cfg.RootCAs = key.rootCAs
+		}
+		if key.pins != nil {
+			cfg.VerifyPeerCertificate = key.pins.verify
+			cfg.ClientSessionCache = nil // Resumed sessions are not verified.
+		}
+		t.TLSClientConfig = cfg
+	}
+	return t
+}
+
+// TLSClientCert authenticates the requests with the client certificate
+// in the PEM files, for servers requiring mutual TLS.
+// The files are loaded once, when TLSClientCert is called.
+// TLSClientCert is ignored if a HTTPClient is set.
+func TLSClientCert(certFile, keyFile string) Option {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	return func(req *request) {
+		if err != nil {
+			req.err = err
+			return
+		}
+		req.clientCert = &cert
+	}
+}
+
+// TLSRootCAs verifies the server certificates with the pool
+// instead of the system roots, such as for a private CA.
+// TLSRootCAs is ignored if a HTTPClient is set.
+func TLSRootCAs(pool *x509.CertPool) Option {
+	return func(req *request) {
+		req.rootCAs = pool
+	}
+}
+
+// dialer is the func of a DialContext option.
+type dialer struct {
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// DialContext makes the connections of the requests with dial,
+// such as for a custom network layer in tests or sandboxes.
+// The connections to a proxy are also made with dial.
+// DialContext is ignored if a HTTPClient is set.
+func DialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) Option {
+	d := &dialer{dial}
+	return func(req *request) {
+		req.dialer = d
+	}
+}
+
+// UnixSocket connects to the server at the unix socket path,
+// use a http BaseURL such as http://localhost/ for the server.
+// See DialContext.
+func UnixSocket(path string) Option {
+	return DialContext(func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", path)
+	})
+}
+
+// certPins are the SHA-256 hashes of the pinned certificates or public keys.
+type certPins struct {
+	sums [][]byte
+}
+
+// verify checks the server leaf certificate or its public key is pinned.
+func (p *certPins) verify(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return errors.New("no server certificate")
+	}
+	cert, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return err
+	}
+	certSum := sha256.Sum256(cert.Raw)
+	keySum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	for _, sum := range p.sums {
+		if bytes.Equal(sum, certSum[:]) || bytes.Equal(sum, keySum[:]) {
+			return nil
+		}
+	}
+	return errors.New("server certificate is not pinned")
+}
+
+// PinCertificate accepts only servers whose leaf certificate or its public key
+// (SubjectPublicKeyInfo) has one of the SHA-256 hashes, in hex or base64,
+// with an optional "sha256/" prefix. It is checked on every connection,
+// in addition to the usual certificate verification.
+// PinCertificate is ignored if a HTTPClient is set.
+func PinCertificate(hashes ...string) Option {
+	pins := &certPins{}
+	var err error
+	for _, pin := range hashes {
+		pin = strings.TrimPrefix(pin, "sha256/")
+		sum, herr := hex.DecodeString(pin)
+		if herr != nil {
+			sum, herr = base64.StdEncoding.DecodeString(pin)
+		}
+		if herr != nil || len(sum) != 32 {
+			err = errors.New("invalid certificate pin: " + pin)
+			break
+		}
+		pins.sums = append(pins.sums, sum)
+	}
+	if err == nil && len(pins.sums) == 0 {
+		err = errors.New("no certificate pins")
+	}
+	return func(req *request) {
+		if err != nil {
+			req.err = err
+			return
+		}
+		req.pins = pins
+	}
+}
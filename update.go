@@ -0,0 +1,58 @@
+package paste
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+)
+
+func update(paste string, r io.Reader, req *request, options ...Option) error {
+	req.apply(options)
+
+	if req.tok == "" {
+		return errors.New("updating a paste requires a token")
+	}
+	id, err := pasteID(paste)
+	if err != nil {
+		return err
+	}
+
+	body, contentType, err := req.uploadBody(r, "")
+	if err != nil {
+		return err
+	}
+	defer body.Close() // Don't hang writes if bailing out.
+
+	hr, err := req.newRequest("PUT", req.endpoint(id), body)
+	if err != nil {
+		return err
+	}
+
+	hr.Header.Set("Content-Type", contentType)
+	if body.encoding != "" {
+		hr.Header.Set("Content-Encoding", body.encoding)
+	}
+
+	resp, err := req.do(hr)
+	if err != nil {
+		return err
+	}
+	result, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return transportError(hr, err)
+	}
+	if resp.StatusCode != 200 && resp.StatusCode != 204 {
+		return apiError(resp, result)
+	}
+	return nil
+}
+
+// Update replaces the content of a paste with r,
+// and its Title, Description and Type when set.
+// paste can be a full paste URL or just the paste ID.
+// Requires the Token of the paste owner.
+// The error is ErrLocked, using errors.Is, if the paste is locked.
+func Update(paste string, r io.Reader, options ...Option) error {
+	return update(paste, r, &request{}, options...)
+}
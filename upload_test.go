@@ -0,0 +1,157 @@
+package paste
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files")
+
+func TestUploadBodyGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		files   []NamedReader
+		options []Option
+	}{
+		{"single", "hello\n", nil, []Option{Title("Hello"), Type("Plain Text"), Tags("a", "b")}},
+		{"charset", "hello\n", nil, []Option{Charset("iso-8859-1")}},
+		// The part Content-Type is from the file extension.
+		{"files", "", []NamedReader{
+			{"data.json", strings.NewReader(`{"a": 1}`)},
+			{"index.html", strings.NewReader("<p>hi</p>")},
+			{"blob", strings.NewReader("\x00\x01")},
+		}, nil},
+		// PartContentType overrides the extension, empty falls back to it.
+		{"part-content-type", "", []NamedReader{
+			{"main.tmpl", strings.NewReader("{{.}}")},
+			{"data.json", strings.NewReader(`{}`)},
+		}, []Option{PartContentType(func(name string) string {
+			if filepath.Ext(name) == ".tmpl" {
+				return "text/x-go-template"
+			}
+			return ""
+		})}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := &request{}
+			req.apply(test.options)
+			req.files = test.files
+			body, contentType, err := req.uploadBody(strings.NewReader(test.content), "golden")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ioutil.ReadAll(body)
+			body.wait()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if contentType != "multipart/form-data; boundary=golden" {
+				t.Errorf("Content-Type = %q", contentType)
+			}
+
+			path := filepath.Join("testdata", "upload-"+test.name+".golden")
+			if *updateGolden {
+				err := ioutil.WriteFile(path, got, 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("body differs from %s:\n%s", path, got)
+			}
+		})
+	}
+}
+
+// endless is paste content which never ends.
+type endless struct{}
+
+func (endless) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'x'
+	}
+	return len(p), nil
+}
+
+// receivingServer reads the first 64 KiB of each request body,
+// signals received, and reads the rest until the client goes away.
+func receivingServer() (*httptest.Server, chan struct{}) {
+	received := make(chan struct{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.CopyN(ioutil.Discard, r.Body, 64<<10)
+		received <- struct{}{}
+		io.Copy(ioutil.Discard, r.Body)
+	}))
+	return srv, received
+}
+
+// checkGoroutines fails if there are more goroutines than before
+// once the exiting ones are done.
+func checkGoroutines(t *testing.T, before int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<20)
+			t.Fatalf("%d goroutines leaked:\n%s", runtime.NumGoroutine()-before, buf[:runtime.Stack(buf, true)])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestUploadCancelNoLeak(t *testing.T) {
+	before := runtime.NumGoroutine()
+	srv, received := receivingServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-received
+		cancel()
+	}()
+	_, err := Upload(endless{}, BaseURL(srv.URL), HTTPClient(srv.Client()), Context(ctx))
+	var terr *TransportError
+	if !errors.As(err, &terr) || !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want a TransportError of context.Canceled", err)
+	}
+
+	srv.Close()
+	checkGoroutines(t, before)
+}
+
+func TestUploadWriterAbortNoLeak(t *testing.T) {
+	before := runtime.NumGoroutine()
+	srv, received := receivingServer()
+
+	w := NewUploadWriter(BaseURL(srv.URL), HTTPClient(srv.Client()))
+	go func() {
+		<-received
+		w.Abort()
+	}()
+	_, err := io.Copy(w, endless{})
+	if err == nil {
+		t.Error("writes after Abort succeeded")
+	}
+	if err := w.Abort(); err != nil {
+		t.Errorf("Abort = %v", err)
+	}
+
+	srv.Close()
+	checkGoroutines(t, before)
+}
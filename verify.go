@@ -0,0 +1,66 @@
+package paste
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/url"
+	"path"
+)
+
+// urlPasteID returns the paste ID of a paste URL returned by an upload.
+func urlPasteID(pasteURL string) (string, error) {
+	u, err := url.Parse(pasteURL)
+	if err != nil {
+		return "", err
+	}
+	id := path.Base(u.Path)
+	if id == "/" || id == "." {
+		return "", errors.New("invalid paste URL")
+	}
+	return id, nil
+}
+
+func uploadVerify(r io.ReadSeeker, req *request, options ...Option) (string, error) {
+	check := *req
+	check.apply(options)
+	if check.maxViews == 1 {
+		return "", errors.New("UploadVerify would use the only view of the paste")
+	}
+	getReq := *req
+	req.sum = sha256.New()
+	pasteURL, err := upload(r, req, options...)
+	if err != nil {
+		return "", err
+	}
+	id, err := urlPasteID(pasteURL)
+	if err != nil {
+		return "", err
+	}
+
+	getReq.decryptKey = req.encryptKey
+	info, err := get(id, &getReq, options...)
+	if err != nil {
+		return "", err
+	}
+	defer info.Content.Close()
+	got := sha256.New()
+	_, err = io.Copy(got, info.Content)
+	if err != nil {
+		return "", err
+	}
+	if !bytes.Equal(got.Sum(nil), req.sum.Sum(nil)) {
+		return "", errors.New("uploaded paste content does not match: " + pasteURL)
+	}
+	return pasteURL, nil
+}
+
+// UploadVerify is like Upload, but then gets the new paste
+// and checks that its content matches what was uploaded.
+// The content is hashed as it is uploaded, from the current offset of r.
+// The check counts as one view of the paste,
+// so it can't be used with BurnAfterRead or MaxViews(1).
+func UploadVerify(r io.ReadSeeker, options ...Option) (string, error) {
+	return uploadVerify(r, &request{}, options...)
+}
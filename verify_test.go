@@ -0,0 +1,28 @@
+package paste_test
+
+import (
+	"strings"
+	"testing"
+
+	"paste.run"
+	"paste.run/pastetest"
+)
+
+func TestUploadVerifyBurnAfterRead(t *testing.T) {
+	srv := pastetest.NewServer()
+	defer srv.Close()
+	options := []paste.Option{paste.NoConfig(), paste.NoEnv(), paste.BaseURL(srv.URL)}
+	for _, option := range []paste.Option{paste.BurnAfterRead(), paste.MaxViews(1)} {
+		_, err := paste.UploadVerify(strings.NewReader("hello"), append(options, option)...)
+		if err == nil {
+			t.Error("UploadVerify of a paste with one view succeeded")
+		}
+	}
+	if srv.Pastes() != 0 {
+		t.Errorf("%d pastes uploaded, want 0", srv.Pastes())
+	}
+	_, err := paste.UploadVerify(strings.NewReader("hello"), append(options, paste.MaxViews(2))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,11 @@
+package paste
+
+import "runtime"
+
+// Version of the package.
+const Version = "0.1.0"
+
+// UserAgentString returns the default User-Agent header for requests.
+func UserAgentString() string {
+	return "paste.run-go/" + Version + " (" + runtime.Version() + ")"
+}
@@ -0,0 +1,87 @@
+package paste
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+var errUploadAborted = errors.New("upload aborted")
+
+// uploadWriterBufferSize is the UploadWriter buffer size.
+const uploadWriterBufferSize = 32 << 10
+
+// UploadWriter uploads a paste as it is written.
+// Writes are buffered, and block while the buffer is full and the
+// content is being sent, so a writer can't outrun the network.
+// Close must be called to finish the upload, or Abort to cancel it.
+// An UploadWriter is not safe for concurrent use.
+type UploadWriter struct {
+	pw   *io.PipeWriter
+	bw   *bufio.Writer
+	done chan struct{}
+	url  string
+	err  error
+}
+
+// NewUploadWriter starts uploading a paste, write the content to the returned UploadWriter.
+func NewUploadWriter(options ...Option) *UploadWriter {
+	return newUploadWriter(&request{}, options...)
+}
+
+func newUploadWriter(req *request, options ...Option) *UploadWriter {
+	pr, pw := io.Pipe()
+	w := &UploadWriter{
+		pw:   pw,
+		bw:   bufio.NewWriterSize(pw, uploadWriterBufferSize),
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(w.done)
+		w.url, w.err = upload(pr, req, options...)
+		// Don't hang writes if the upload finished early.
+		if w.err != nil {
+			pr.CloseWithError(w.err)
+		} else {
+			pr.Close()
+		}
+	}()
+	return w
+}
+
+// Write writes paste content.
+func (w *UploadWriter) Write(p []byte) (int, error) {
+	return w.bw.Write(p)
+}
+
+// Flush sends the buffered content, it returns once the content
+// was handed to the HTTP transport.
+func (w *UploadWriter) Flush() error {
+	return w.bw.Flush()
+}
+
+// Close flushes the buffered content, finishes the upload
+// and waits for the server response.
+func (w *UploadWriter) Close() error {
+	err := w.bw.Flush()
+	if err != nil {
+		w.pw.CloseWithError(err)
+	} else {
+		w.pw.Close()
+	}
+	<-w.done
+	return w.err
+}
+
+// Abort cancels the upload and waits for it to stop,
+// the partial paste is not created.
+func (w *UploadWriter) Abort() error {
+	w.pw.CloseWithError(errUploadAborted)
+	<-w.done
+	return nil
+}
+
+// URL returns the new paste URL after Close returns nil.
+func (w *UploadWriter) URL() string {
+	return w.url
+}